@@ -0,0 +1,104 @@
+package lru
+
+// Acquire is like Get, but also pins the entry: it won't be evicted for
+// capacity until Release has been called for key as often as Acquire, so
+// Hooks.OnEvict can't free a value that is still in use. A full cache
+// whose eviction candidates are all pinned grows past ItemLimit, and the
+// last Release evicts the excess. Pins belong to the key, not the entry:
+// Delete, expiry, Clear and Set still remove pinned values, but the hooks
+// only hear about them once the last Release is done. Aliases can be
+// acquired and released in place of the primary key.
+func (cache *LRUCache[K, V]) Acquire(key K) (V, error) {
+	cache.init()
+	defer cache.notifyRemoved()
+	cache.Storage.mu.Lock()
+	defer cache.Storage.mu.Unlock()
+	key = cache.resolve(key)
+	frozen := cache.frozen.Load() != nil
+	now := cache.now()
+	storageItem, exists := cache.Storage.SafeMap[key]
+	err := ErrKeyNotFound
+	if exists && !frozen && cache.expired(storageItem, now) {
+		cache.expireKey(key, now)
+		exists = false
+		err = ErrKeyExpired
+	}
+	if !exists && !frozen {
+		storageItem, exists = cache.readmit(key, now)
+	}
+	if !exists {
+		if !frozen {
+			cache.recordMiss(key)
+		}
+		cache.stats.misses.Add(1)
+		var zero V
+		return zero, err
+	}
+	cache.stats.hits.Add(1)
+	if !frozen {
+		cache.touch(key, storageItem)
+	}
+	if cache.pins == nil {
+		cache.pins = make(map[K]int)
+	}
+	cache.pins[key]++
+	return storageItem.Value, nil
+}
+
+// Release undoes one Acquire of key. Releasing a key that isn't pinned
+// does nothing. The last Release reports the values removed while key was
+// pinned, and evicts down to ItemLimit unless the cache is frozen.
+func (cache *LRUCache[K, V]) Release(key K) {
+	cache.init()
+	var cleared, evicted []Entry[K, V]
+	defer func() {
+		cache.notifyRemoved()
+		cache.notifyEvicted(cleared, Cleared)
+		cache.notifyEvicted(evicted, CapacityEvicted)
+	}()
+	cache.Storage.mu.Lock()
+	defer cache.Storage.mu.Unlock()
+	key = cache.resolve(key)
+	if cache.pins[key] == 0 {
+		return
+	}
+	if cache.pins[key]--; cache.pins[key] > 0 {
+		return
+	}
+	delete(cache.pins, key)
+	for _, held := range cache.held[key] {
+		if held.reason == Cleared {
+			cleared = append(cleared, held.Entry)
+		} else {
+			cache.removals = append(cache.removals, held)
+		}
+	}
+	delete(cache.held, key)
+	if cache.frozen.Load() != nil {
+		return
+	}
+	for cache.Config.ItemLimit > 0 && int64(len(cache.Storage.SafeMap)) > cache.Config.ItemLimit {
+		entries := cache.removeOldestKey()
+		if entries == nil {
+			break
+		}
+		evicted = append(evicted, entries...)
+	}
+}
+
+// Pinned reports whether key has been acquired and not yet released.
+func (cache *LRUCache[K, V]) Pinned(key K) bool {
+	cache.init()
+	cache.Storage.mu.RLock()
+	defer cache.Storage.mu.RUnlock()
+	return cache.pins[cache.resolve(key)] > 0
+}
+
+// hold keeps a removal of pinned key from the hooks until the last
+// Release. Callers must hold the write lock.
+func (cache *LRUCache[K, V]) hold(key K, removed removal[K, V]) {
+	if cache.held == nil {
+		cache.held = make(map[K][]removal[K, V])
+	}
+	cache.held[key] = append(cache.held[key], removed)
+}
@@ -0,0 +1,180 @@
+package lru
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheAcquire(t *testing.T) {
+	alice := UserData{ID: 1, Name: "Alice", Age: 30}
+	bob := UserData{ID: 2, Name: "Bob", Age: 25}
+
+	t.Run("returns the value and pins it", func(t *testing.T) {
+		lruCache := InMemoryLRUCacheProvider[UserData]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		value, err := lruCache.Acquire("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, alice, value)
+		assert.True(t, lruCache.Pinned("user1"))
+
+		_, err = lruCache.Acquire("user2")
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+		assert.False(t, lruCache.Pinned("user2"))
+		assert.Equal(t, uint64(1), lruCache.Stats().Hits)
+		assert.Equal(t, uint64(1), lruCache.Stats().Misses)
+	})
+
+	t.Run("skips pinned entries when evicting", func(t *testing.T) {
+		for _, eviction := range []EvictionPolicy{LRUEviction, TwoQueueEviction, FIFOEviction, ClockEviction, SegmentedLRUEviction} {
+			lruCache := InMemoryLRUCacheProvider[UserData]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 2, Eviction: eviction}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", alice)
+			lruCache.Set("user2", bob)
+			_, _ = lruCache.Acquire("user1")
+			lruCache.Set("user3", bob)
+			assert.True(t, lruCache.Has("user1"), eviction.String())
+			assert.False(t, lruCache.Has("user2"), eviction.String())
+			assert.True(t, lruCache.Has("user3"), eviction.String())
+		}
+	})
+
+	t.Run("skips pinned entries behind TinyLFU", func(t *testing.T) {
+		lruCache := InMemoryLRUCacheProvider[UserData]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 3, Admission: TinyLFUAdmission}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		_, _ = lruCache.Acquire("user1")
+		for _, key := range []string{"user2", "user3", "user4", "user5", "user6"} {
+			lruCache.Set(key, bob)
+		}
+		assert.True(t, lruCache.Has("user1"))
+		assert.Equal(t, 3, lruCache.Len())
+	})
+
+	t.Run("leaves skipped entries in place", func(t *testing.T) {
+		lruCache := InMemoryLRUCacheProvider[UserData]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 8, Eviction: TwoQueueEviction}).(*InMemoryLRUCache[UserData])
+		for i := 1; i <= 8; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), alice)
+		}
+		_, _ = lruCache.Acquire("user1")
+		lruCache.Set("user9", bob)
+		assert.False(t, lruCache.policy.(*twoQueue[string]).queued["user1"].main, "Skipping shouldn't make 'user1' hot")
+
+		lruCache.Release("user1")
+		lruCache.Set("user10", bob)
+		assert.False(t, lruCache.Has("user1"))
+		assert.True(t, lruCache.Has("user3"))
+
+		lruCache = InMemoryLRUCacheProvider[UserData]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 3, Admission: TinyLFUAdmission}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", alice)
+		lruCache.Set("user3", alice)
+		_, _ = lruCache.Acquire("user1")
+		policy := lruCache.policy.(*tinyLFU[string])
+		estimate := policy.sketch.estimate("user1")
+		for _, key := range []string{"user4", "user5", "user6", "user7"} {
+			// missed often enough to be admitted over the main victim
+			lruCache.Get(key)
+			lruCache.Get(key)
+			lruCache.Set(key, bob)
+		}
+		assert.Equal(t, estimate, policy.sketch.estimate("user1"), "Skipping shouldn't count as a request")
+		assert.NotContains(t, policy.windowKeys, "user1")
+	})
+
+	t.Run("defers eviction until the last release", func(t *testing.T) {
+		var evicted []string
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{OnEvict: func(key string, value UserData, reason EvictionReason) {
+			evicted = append(evicted, key)
+		}}}
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", bob)
+		_, _ = lruCache.Acquire("user1")
+		_, _ = lruCache.Acquire("user2")
+		_, _ = lruCache.Acquire("user2")
+		lruCache.Set("user3", bob)
+		assert.Equal(t, 3, lruCache.Len())
+		assert.Empty(t, evicted)
+
+		lruCache.Release("user1")
+		assert.Equal(t, []string{"user1"}, evicted)
+		lruCache.Release("user2")
+		assert.Equal(t, 2, lruCache.Len())
+		assert.True(t, lruCache.Pinned("user2"))
+		lruCache.Release("user2")
+		assert.False(t, lruCache.Pinned("user2"))
+		assert.Equal(t, []string{"user1"}, evicted)
+	})
+
+	t.Run("doesn't evict from a frozen cache on release", func(t *testing.T) {
+		lruCache := InMemoryLRUCacheProvider[UserData]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 1}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		_, _ = lruCache.Acquire("user1")
+		lruCache.Set("user2", bob)
+		lruCache.Freeze()
+		lruCache.Release("user1")
+		assert.False(t, lruCache.Pinned("user1"))
+		assert.Equal(t, 2, lruCache.Len())
+		assert.Zero(t, lruCache.Stats().Evictions)
+	})
+
+	t.Run("ignores unbalanced releases", func(t *testing.T) {
+		lruCache := InMemoryLRUCacheProvider[UserData]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 2}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		lruCache.Release("user1")
+		_, _ = lruCache.Acquire("user1")
+		lruCache.Release("user1")
+		lruCache.Release("user1")
+		assert.False(t, lruCache.Pinned("user1"))
+		assert.True(t, lruCache.Has("user1"))
+	})
+
+	t.Run("reports removed values after the last release", func(t *testing.T) {
+		var evicted []string
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{OnEvict: func(key string, value UserData, reason EvictionReason) {
+			evicted = append(evicted, key+" "+value.Name+" "+reason.String())
+		}}}
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 20, ExpiryTick: 10000}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", alice)
+		lruCache.SetWithTTL("user3", alice, time.Hour)
+		for _, key := range []string{"user1", "user2", "user3"} {
+			_, _ = lruCache.Acquire(key)
+		}
+		lruCache.Set("user1", bob)
+		lruCache.Delete("user1")
+		time.Sleep(40 * time.Millisecond)
+		assert.False(t, lruCache.Has("user2"))
+		lruCache.Clear()
+		assert.Empty(t, evicted)
+
+		lruCache.Release("user1")
+		assert.Equal(t, []string{"user1 Alice replaced", "user1 Bob deleted"}, evicted)
+		lruCache.Release("user2")
+		lruCache.Release("user3")
+		assert.Equal(t, []string{"user1 Alice replaced", "user1 Bob deleted", "user2 Alice expired", "user3 Alice cleared"}, evicted)
+	})
+
+	t.Run("pins aliases as their primary key", func(t *testing.T) {
+		lruCache := InMemoryLRUCacheProvider[UserData]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.SetWithAliases("user1", alice, "alice")
+		value, err := lruCache.Acquire("alice")
+		assert.NoError(t, err)
+		assert.Equal(t, alice, value)
+		assert.True(t, lruCache.Pinned("user1"))
+		assert.True(t, lruCache.Pinned("alice"))
+		lruCache.Release("alice")
+		assert.False(t, lruCache.Pinned("user1"))
+	})
+
+	t.Run("doesn't keep deleted entries", func(t *testing.T) {
+		lruCache := InMemoryLRUCacheProvider[UserData]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 2}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		_, _ = lruCache.Acquire("user1")
+		lruCache.Delete("user1")
+		assert.False(t, lruCache.Has("user1"))
+		lruCache.Release("user1")
+		assert.False(t, lruCache.Pinned("user1"))
+	})
+}
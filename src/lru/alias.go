@@ -0,0 +1,122 @@
+package lru
+
+// SetWithAliases sets primary to value and makes every alias another key
+// for the same entry, e.g. an object's slug next to its ID. Has, Get, Peek,
+// GetOrSet, Set and Delete accept aliases in place of the primary key; the
+// other methods, as well as Keys and DeletePrefix, only know primary keys.
+// The aliases replace any set before for primary and are kept when it is
+// overwritten with Set. An alias that was a key of its own drops that
+// entry, unless it is read-only, and an alias of another entry is moved
+// over. Once the entry is removed, whether through Delete on any of its
+// keys, eviction or expiry, its aliases are gone too. Aliases aren't saved
+// by SaveTo.
+func (cache *LRUCache[K, V]) SetWithAliases(primary K, value V, aliases ...K) V {
+	if cache.Config.ValidateOnSet && cache.validate(primary, value) != nil {
+		return value
+	}
+	cache.init()
+	size := cache.weigh(primary, value)
+	var evicted []Entry[K, V]
+	defer func() {
+		cache.notifyRemoved()
+		cache.notifyEvicted(evicted, CapacityEvicted)
+	}()
+	cache.Storage.mu.Lock()
+	defer cache.Storage.mu.Unlock()
+	if cache.rejectFrozenWrite() || cache.rejectReadOnlyWrite(primary) {
+		return value
+	}
+	cache.unalias(primary)
+	evicted = cache.store(primary, value, nil, 0, size)
+	now := cache.now()
+	cache.dropAliases(primary)
+	for _, alias := range aliases {
+		if alias == primary || cache.readOnly(alias) {
+			continue
+		}
+		cache.unalias(alias)
+		cache.forgetVictim(alias, Replaced)
+		cache.expireKey(alias, now)
+		if item, exists := cache.Storage.SafeMap[alias]; exists {
+			cache.recordRemoval(alias, item.Value, Replaced)
+			cache.deleteKey(alias)
+		}
+		cache.dropAliases(alias)
+		if cache.aliases == nil {
+			cache.aliases = make(map[K]K)
+			cache.aliasesOf = make(map[K][]K)
+		}
+		cache.aliases[alias] = primary
+		cache.aliasesOf[primary] = append(cache.aliasesOf[primary], alias)
+	}
+	cache.publishAliases(primary)
+	return value
+}
+
+// Aliases returns the aliases of primary, see SetWithAliases.
+func (cache *LRUCache[K, V]) Aliases(primary K) []K {
+	if cache.frozen.Load() != nil {
+		return append([]K(nil), cache.aliasesOf[primary]...)
+	}
+	cache.init()
+	cache.Storage.mu.RLock()
+	defer cache.Storage.mu.RUnlock()
+	return append([]K(nil), cache.aliasesOf[primary]...)
+}
+
+// resolve returns the primary key of an alias, and any other key as is.
+// Callers must hold the lock, unless the cache is frozen.
+func (cache *LRUCache[K, V]) resolve(key K) K {
+	if primary, aliased := cache.aliases[key]; aliased {
+		return primary
+	}
+	return key
+}
+
+// unalias detaches key from the entry it is an alias of, if any. Callers
+// must hold the write lock.
+func (cache *LRUCache[K, V]) unalias(key K) {
+	primary, aliased := cache.aliases[key]
+	if !aliased {
+		return
+	}
+	delete(cache.aliases, key)
+	cache.unpublish(key)
+	aliases := cache.aliasesOf[primary]
+	for i, alias := range aliases {
+		if alias == key {
+			aliases = append(aliases[:i], aliases[i+1:]...)
+			break
+		}
+	}
+	if len(aliases) == 0 {
+		delete(cache.aliasesOf, primary)
+		return
+	}
+	cache.aliasesOf[primary] = aliases
+}
+
+// dropAliases forgets the aliases of primary once its entry is gone for
+// good. Entries in the victim cache keep them, and so do entries
+// overwritten by store. Callers must hold the write lock.
+func (cache *LRUCache[K, V]) dropAliases(primary K) {
+	for _, alias := range cache.aliasesOf[primary] {
+		delete(cache.aliases, alias)
+		cache.unpublish(alias)
+	}
+	delete(cache.aliasesOf, primary)
+}
+
+// renameAliases moves the aliases of oldKey over to newKey. Callers must
+// hold the write lock.
+func (cache *LRUCache[K, V]) renameAliases(oldKey, newKey K) {
+	aliases, exists := cache.aliasesOf[oldKey]
+	if !exists {
+		return
+	}
+	for _, alias := range aliases {
+		cache.aliases[alias] = newKey
+	}
+	delete(cache.aliasesOf, oldKey)
+	cache.aliasesOf[newKey] = aliases
+}
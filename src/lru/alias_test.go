@@ -0,0 +1,94 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheAliases(t *testing.T) {
+	alice := UserData{ID: 1, Name: "Alice", Age: 30}
+	bob := UserData{ID: 2, Name: "Bob", Age: 25}
+	newCache := func(config LRUCacheConfig) *InMemoryLRUCache[UserData] {
+		return InMemoryLRUCacheProvider[UserData]{}.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+	}
+
+	t.Run("resolves aliases to the same entry", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 10})
+		lruCache.SetWithAliases("user:1", alice, "user:alice", "u1")
+		value, err := lruCache.Get("user:alice")
+		assert.NoError(t, err)
+		assert.Equal(t, alice, value)
+		assert.True(t, lruCache.Has("u1"))
+
+		lruCache.Set("u1", bob)
+		value, _ = lruCache.Peek("user:1")
+		assert.Equal(t, bob, value)
+		assert.Equal(t, []string{"user:1"}, lruCache.Keys())
+		assert.Equal(t, []string{"user:alice", "u1"}, lruCache.Aliases("user:1"))
+	})
+
+	t.Run("deleting any key removes the aliases", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 10})
+		lruCache.SetWithAliases("user:1", alice, "user:alice", "u1")
+		assert.True(t, lruCache.Delete("u1"))
+		assert.False(t, lruCache.Has("user:1"))
+		assert.False(t, lruCache.Has("user:alice"))
+		assert.Empty(t, lruCache.Aliases("user:1"))
+
+		lruCache.Set("user:alice", bob)
+		assert.Equal(t, []string{"user:alice"}, lruCache.Keys())
+	})
+
+	t.Run("evicted and expired entries lose their aliases", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1})
+		lruCache.SetWithAliases("user:1", alice, "user:alice")
+		lruCache.Set("user:2", bob)
+		assert.False(t, lruCache.Has("user:alice"))
+		assert.Empty(t, lruCache.Aliases("user:1"))
+
+		lruCache = newCache(LRUCacheConfig{TTL: 20, ExpiryTick: 10000})
+		lruCache.SetWithAliases("user:1", alice, "user:alice")
+		time.Sleep(40 * time.Millisecond)
+		_, err := lruCache.Get("user:alice")
+		assert.Error(t, err)
+		assert.Empty(t, lruCache.Aliases("user:1"))
+	})
+
+	t.Run("takes over keys", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 10})
+		lruCache.Set("user:alice", bob)
+		lruCache.SetWithAliases("user:1", alice, "user:alice")
+		assert.Equal(t, []string{"user:1"}, lruCache.Keys())
+		assert.Equal(t, uint64(1), lruCache.Stats().Replacements)
+
+		lruCache.SetWithAliases("user:2", bob, "user:alice")
+		value, _ := lruCache.Get("user:alice")
+		assert.Equal(t, bob, value)
+		assert.Empty(t, lruCache.Aliases("user:1"))
+
+		lruCache.SetWithAliases("user:2", bob, "user:bob")
+		assert.Equal(t, []string{"user:bob"}, lruCache.Aliases("user:2"))
+		assert.False(t, lruCache.Has("user:alice"))
+	})
+
+	t.Run("keeps aliases in the victim cache", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1, VictimCacheSize: 1})
+		lruCache.SetWithAliases("user:1", alice, "user:alice")
+		lruCache.Set("user:2", bob)
+		value, err := lruCache.Get("user:alice")
+		assert.NoError(t, err)
+		assert.Equal(t, alice, value)
+		assert.Equal(t, uint64(1), lruCache.Stats().PrematureEvictions)
+	})
+
+	t.Run("follows renames", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 10})
+		lruCache.SetWithAliases("user:1", alice, "user:alice", "u1")
+		assert.NoError(t, lruCache.Rename("user:1", "u1", false))
+		assert.Equal(t, []string{"user:alice"}, lruCache.Aliases("u1"))
+		value, _ := lruCache.Get("user:alice")
+		assert.Equal(t, alice, value)
+	})
+}
@@ -0,0 +1,208 @@
+package authcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lru"
+)
+
+type Config struct {
+	ItemLimit int64
+	// MaxAge caps how long a response is considered fresh, whatever its
+	// Cache-Control header or token exp says. Zero leaves freshness to the
+	// header and exp alone.
+	MaxAge time.Duration
+	// StaleIfError lets an expired response be served for up to this long
+	// when refreshing it fails. A stale-if-error Cache-Control directive can
+	// shorten the window but not extend it.
+	StaleIfError time.Duration
+	HTTPClient   *http.Client
+}
+
+type cached[T any] struct {
+	value      T
+	freshUntil time.Time
+	staleUntil time.Time
+}
+
+func newCache[T any](config Config) lru.LRUCacher[cached[T]] {
+	var ttl int64
+	if config.MaxAge > 0 {
+		// without a cap, entries stay until evicted and lookup checks them
+		ttl = (config.MaxAge + config.StaleIfError).Milliseconds()
+	}
+	return lru.InMemoryLRUCacheProvider[cached[T]]{}.NewLRUCache(lru.LRUCacheConfig{
+		ItemLimit: config.ItemLimit,
+		TTL:       ttl,
+		TTLMode:   lru.AbsoluteTTL,
+	})
+}
+
+func lookup[T any](cache lru.LRUCacher[cached[T]], key string, fetch func(now time.Time) (cached[T], error)) (T, error) {
+	now := time.Now()
+	entry, err := cache.Get(key)
+	if err == nil && now.Before(entry.freshUntil) {
+		return entry.value, nil
+	}
+	fetched, fetchErr := fetch(now)
+	if fetchErr != nil {
+		if err == nil && now.Before(entry.staleUntil) {
+			return entry.value, nil
+		}
+		return fetched.value, fetchErr
+	}
+	if fetched.freshUntil.After(now) {
+		cache.Set(key, fetched)
+	}
+	return fetched.value, nil
+}
+
+type JSONWebKey struct {
+	KeyID     string `json:"kid"`
+	KeyType   string `json:"kty"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+	// Raw is the key exactly as published, for handing to a JOSE library.
+	Raw json.RawMessage `json:"-"`
+}
+
+func (key *JSONWebKey) UnmarshalJSON(data []byte) error {
+	type plain JSONWebKey
+	if err := json.Unmarshal(data, (*plain)(key)); err != nil {
+		return err
+	}
+	key.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+type KeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+func (set KeySet) Key(keyID string) (JSONWebKey, bool) {
+	for _, key := range set.Keys {
+		if key.KeyID == keyID {
+			return key, true
+		}
+	}
+	return JSONWebKey{}, false
+}
+
+// JWKSCache caches JWKS documents by URL for as long as their Cache-Control
+// header allows.
+type JWKSCache struct {
+	config Config
+	cache  lru.LRUCacher[cached[KeySet]]
+}
+
+func NewJWKSCache(config Config) *JWKSCache {
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &JWKSCache{config: config, cache: newCache[KeySet](config)}
+}
+
+func (jwks *JWKSCache) Get(ctx context.Context, url string) (KeySet, error) {
+	return lookup(jwks.cache, url, func(now time.Time) (cached[KeySet], error) {
+		return jwks.fetch(ctx, url, now)
+	})
+}
+
+func (jwks *JWKSCache) fetch(ctx context.Context, url string, now time.Time) (cached[KeySet], error) {
+	var entry cached[KeySet]
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return entry, err
+	}
+	resp, err := jwks.config.HTTPClient.Do(req)
+	if err != nil {
+		return entry, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return entry, fmt.Errorf("fetching JWKS from %s: unexpected status %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entry.value); err != nil {
+		return entry, fmt.Errorf("decoding JWKS from %s: %w", url, err)
+	}
+
+	maxAge, staleIfError := parseCacheControl(resp.Header.Get("Cache-Control"), jwks.config)
+	entry.freshUntil = now.Add(maxAge)
+	entry.staleUntil = entry.freshUntil.Add(staleIfError)
+	return entry, nil
+}
+
+func parseCacheControl(header string, config Config) (maxAge time.Duration, staleIfError time.Duration) {
+	maxAge, staleIfError = config.MaxAge, config.StaleIfError
+	for _, directive := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+		switch strings.ToLower(name) {
+		case "no-store", "no-cache":
+			return 0, 0
+		case "max-age":
+			if err == nil && (config.MaxAge <= 0 || time.Duration(seconds)*time.Second < maxAge) {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+		case "stale-if-error":
+			if err == nil {
+				staleIfError = min(staleIfError, time.Duration(seconds)*time.Second)
+			}
+		}
+	}
+	return maxAge, staleIfError
+}
+
+type Introspection struct {
+	Active    bool   `json:"active"`
+	Subject   string `json:"sub,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+type IntrospectFunc func(ctx context.Context, token string) (Introspection, error)
+
+// IntrospectionCache caches token introspection results until the token's
+// exp (or MaxAge, whichever comes first). Stale results are never served
+// past exp. Without a MaxAge, results without an exp aren't cached.
+type IntrospectionCache struct {
+	config     Config
+	introspect IntrospectFunc
+	cache      lru.LRUCacher[cached[Introspection]]
+}
+
+func NewIntrospectionCache(config Config, introspect IntrospectFunc) *IntrospectionCache {
+	return &IntrospectionCache{config: config, introspect: introspect, cache: newCache[Introspection](config)}
+}
+
+func (introspection *IntrospectionCache) Introspect(ctx context.Context, token string) (Introspection, error) {
+	digest := sha256.Sum256([]byte(token))
+	return lookup(introspection.cache, hex.EncodeToString(digest[:]), func(now time.Time) (cached[Introspection], error) {
+		result, err := introspection.introspect(ctx, token)
+		entry := cached[Introspection]{value: result}
+		if err != nil {
+			return entry, err
+		}
+		entry.freshUntil = now.Add(introspection.config.MaxAge)
+		entry.staleUntil = entry.freshUntil.Add(introspection.config.StaleIfError)
+		if result.Active && result.ExpiresAt != 0 {
+			exp := time.Unix(result.ExpiresAt, 0)
+			if introspection.config.MaxAge <= 0 || exp.Before(entry.freshUntil) {
+				entry.freshUntil = exp
+			}
+			if introspection.config.MaxAge <= 0 || exp.Before(entry.staleUntil) {
+				entry.staleUntil = exp
+			}
+		}
+		return entry, nil
+	})
+}
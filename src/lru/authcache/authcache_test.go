@@ -0,0 +1,170 @@
+package authcache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const jwksBody = `{"keys":[{"kid":"key1","kty":"RSA","alg":"RS256","use":"sig","n":"abc","e":"AQAB"}]}`
+
+func TestJWKSCache(t *testing.T) {
+	newServer := func(cacheControl string, failing *atomic.Bool, hits *atomic.Int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			if failing != nil && failing.Load() {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Cache-Control", cacheControl)
+			w.Write([]byte(jwksBody))
+		}))
+	}
+
+	t.Run("caches the key set for its max-age", func(t *testing.T) {
+		var hits atomic.Int32
+		server := newServer("public, max-age=60", nil, &hits)
+		defer server.Close()
+		jwks := NewJWKSCache(Config{ItemLimit: 10, MaxAge: time.Hour})
+
+		for i := 0; i < 3; i++ {
+			keySet, err := jwks.Get(context.Background(), server.URL)
+			assert.NoError(t, err)
+			key, ok := keySet.Key("key1")
+			assert.True(t, ok)
+			assert.Equal(t, "RS256", key.Algorithm)
+			assert.JSONEq(t, `{"kid":"key1","kty":"RSA","alg":"RS256","use":"sig","n":"abc","e":"AQAB"}`, string(key.Raw))
+		}
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("caches for the max-age without a MaxAge", func(t *testing.T) {
+		var hits atomic.Int32
+		server := newServer("public, max-age=60", nil, &hits)
+		defer server.Close()
+		jwks := NewJWKSCache(Config{ItemLimit: 10})
+
+		for i := 0; i < 3; i++ {
+			_, err := jwks.Get(context.Background(), server.URL)
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("does not cache no-store responses", func(t *testing.T) {
+		var hits atomic.Int32
+		server := newServer("no-store", nil, &hits)
+		defer server.Close()
+		jwks := NewJWKSCache(Config{ItemLimit: 10, MaxAge: time.Hour})
+
+		jwks.Get(context.Background(), server.URL)
+		jwks.Get(context.Background(), server.URL)
+		assert.Equal(t, int32(2), hits.Load())
+	})
+
+	t.Run("serves stale key set when refresh fails", func(t *testing.T) {
+		var hits atomic.Int32
+		var failing atomic.Bool
+		server := newServer("", &failing, &hits)
+		defer server.Close()
+		jwks := NewJWKSCache(Config{ItemLimit: 10, MaxAge: 100 * time.Millisecond, StaleIfError: time.Second})
+
+		_, err := jwks.Get(context.Background(), server.URL)
+		assert.NoError(t, err)
+
+		failing.Store(true)
+		time.Sleep(150 * time.Millisecond)
+		keySet, err := jwks.Get(context.Background(), server.URL)
+		assert.NoError(t, err, "Stale key set should be served while the endpoint fails")
+		assert.Len(t, keySet.Keys, 1)
+		assert.Equal(t, int32(2), hits.Load())
+	})
+
+	t.Run("returns the error once nothing usable is cached", func(t *testing.T) {
+		var hits atomic.Int32
+		var failing atomic.Bool
+		failing.Store(true)
+		server := newServer("", &failing, &hits)
+		defer server.Close()
+		jwks := NewJWKSCache(Config{ItemLimit: 10, MaxAge: time.Minute})
+
+		_, err := jwks.Get(context.Background(), server.URL)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseCacheControl(t *testing.T) {
+	config := Config{MaxAge: time.Hour, StaleIfError: time.Minute}
+
+	maxAge, staleIfError := parseCacheControl("max-age=30, stale-if-error=10", config)
+	assert.Equal(t, 30*time.Second, maxAge)
+	assert.Equal(t, 10*time.Second, staleIfError)
+
+	maxAge, staleIfError = parseCacheControl("max-age=86400, stale-if-error=86400", config)
+	assert.Equal(t, time.Hour, maxAge, "MaxAge should cap the header")
+	assert.Equal(t, time.Minute, staleIfError, "StaleIfError should cap the header")
+
+	maxAge, _ = parseCacheControl("no-cache", config)
+	assert.Zero(t, maxAge)
+
+	maxAge, _ = parseCacheControl("max-age=86400", Config{})
+	assert.Equal(t, 24*time.Hour, maxAge, "Without a MaxAge the header applies as is")
+	maxAge, _ = parseCacheControl("public", Config{})
+	assert.Zero(t, maxAge)
+}
+
+func TestIntrospectionCache(t *testing.T) {
+	t.Run("caches results until the token expires", func(t *testing.T) {
+		var calls atomic.Int32
+		exp := time.Now().Add(1500 * time.Millisecond).Unix()
+		introspection := NewIntrospectionCache(Config{ItemLimit: 10, MaxAge: time.Hour}, func(ctx context.Context, token string) (Introspection, error) {
+			calls.Add(1)
+			return Introspection{Active: true, Subject: "alice", ExpiresAt: exp}, nil
+		})
+
+		result, err := introspection.Introspect(context.Background(), "token1")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", result.Subject)
+		introspection.Introspect(context.Background(), "token1")
+		assert.Equal(t, int32(1), calls.Load())
+
+		time.Sleep(time.Until(time.Unix(exp, 0)) + 50*time.Millisecond)
+		introspection.Introspect(context.Background(), "token1")
+		assert.Equal(t, int32(2), calls.Load(), "Expired token should be introspected again")
+	})
+
+	t.Run("caches results until exp without a MaxAge", func(t *testing.T) {
+		var calls atomic.Int32
+		introspection := NewIntrospectionCache(Config{ItemLimit: 10}, func(ctx context.Context, token string) (Introspection, error) {
+			calls.Add(1)
+			return Introspection{Active: true, ExpiresAt: time.Now().Add(time.Hour).Unix()}, nil
+		})
+
+		introspection.Introspect(context.Background(), "token1")
+		introspection.Introspect(context.Background(), "token1")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("never serves stale results past exp", func(t *testing.T) {
+		var failing atomic.Bool
+		exp := time.Now().Add(time.Second).Unix()
+		introspection := NewIntrospectionCache(Config{ItemLimit: 10, MaxAge: time.Hour, StaleIfError: time.Hour}, func(ctx context.Context, token string) (Introspection, error) {
+			if failing.Load() {
+				return Introspection{}, errors.New("introspection endpoint down")
+			}
+			return Introspection{Active: true, ExpiresAt: exp}, nil
+		})
+
+		introspection.Introspect(context.Background(), "token1")
+		failing.Store(true)
+		time.Sleep(time.Until(time.Unix(exp, 0)) + 50*time.Millisecond)
+		_, err := introspection.Introspect(context.Background(), "token1")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,125 @@
+package lru
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Background runs periodic work, such as sweeping expired entries and
+// sampling stats, on a capped number of goroutines shared by all its users.
+// Caches without one start goroutines of their own, which adds up when
+// embedding many caches. Work that comes due while all goroutines are busy
+// waits in a queue; periodic work that is still queued or running when it
+// comes due again skips that run.
+type Background struct {
+	max int
+
+	mu      sync.Mutex
+	workers int
+	queue   []*backgroundTask
+}
+
+type backgroundTask struct {
+	run func()
+	// closed when run returned or the task was dropped
+	done chan struct{}
+}
+
+// NewBackground returns a Background running at most goroutines
+// goroutines, 1 if goroutines is zero or less.
+func NewBackground(goroutines int) *Background {
+	return &Background{max: max(1, goroutines)}
+}
+
+// Go queues fn to run on one of the goroutines.
+func (background *Background) Go(fn func()) {
+	background.submit(fn)
+}
+
+func (background *Background) submit(fn func()) *backgroundTask {
+	task := &backgroundTask{run: fn, done: make(chan struct{})}
+	background.mu.Lock()
+	defer background.mu.Unlock()
+	background.queue = append(background.queue, task)
+	if background.workers < background.max {
+		background.workers++
+		go background.work()
+	}
+	return task
+}
+
+func (background *Background) work() {
+	for {
+		background.mu.Lock()
+		if len(background.queue) == 0 {
+			background.workers--
+			background.mu.Unlock()
+			return
+		}
+		task := background.queue[0]
+		background.queue = background.queue[1:]
+		background.mu.Unlock()
+		task.run()
+		close(task.done)
+	}
+}
+
+// drop removes task from the queue and reports whether it was still queued.
+func (background *Background) drop(task *backgroundTask) bool {
+	background.mu.Lock()
+	defer background.mu.Unlock()
+	i := slices.Index(background.queue, task)
+	if i < 0 {
+		return false
+	}
+	background.queue = slices.Delete(background.queue, i, i+1)
+	close(task.done)
+	return true
+}
+
+// Every runs fn every interval until stop is called. Timers don't hold a
+// goroutine while waiting. stop doesn't wait for a run in progress, so fn
+// may call it.
+func (background *Background) Every(interval time.Duration, fn func()) (stop func()) {
+	stopEvery := background.every(interval, fn)
+	return func() { stopEvery(false) }
+}
+
+// every is like Every, but stop(true) also waits for a run in progress to
+// finish, in which case it must not be called from fn.
+func (background *Background) every(interval time.Duration, fn func()) (stop func(wait bool)) {
+	var mu sync.Mutex
+	var pending *backgroundTask
+	stopped := false
+	var timer *time.Timer
+	// held until timer is set, in case it fires right away
+	mu.Lock()
+	defer mu.Unlock()
+	timer = time.AfterFunc(interval, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if stopped {
+			return
+		}
+		if pending == nil {
+			pending = background.submit(func() {
+				fn()
+				mu.Lock()
+				defer mu.Unlock()
+				pending = nil
+			})
+		}
+		timer.Reset(interval)
+	})
+	return func(wait bool) {
+		mu.Lock()
+		stopped = true
+		timer.Stop()
+		task := pending
+		mu.Unlock()
+		if task != nil && !background.drop(task) && wait {
+			<-task.done
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package lru
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackground(t *testing.T) {
+	t.Run("runs at most the given number of goroutines", func(t *testing.T) {
+		background := NewBackground(2)
+		var running, peak atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			background.Go(func() {
+				defer wg.Done()
+				n := running.Add(1)
+				for {
+					current := peak.Load()
+					if n <= current || peak.CompareAndSwap(current, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				running.Add(-1)
+			})
+		}
+		wg.Wait()
+		assert.Equal(t, int32(2), peak.Load())
+	})
+
+	t.Run("Every runs until stopped", func(t *testing.T) {
+		background := NewBackground(1)
+		var runs atomic.Int32
+		stop := background.Every(10*time.Millisecond, func() { runs.Add(1) })
+		assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+		stop()
+		stopped := runs.Load()
+		time.Sleep(50 * time.Millisecond)
+		assert.LessOrEqual(t, runs.Load(), stopped+1, "At most a run in progress should finish")
+	})
+
+	t.Run("Every skips runs while the previous one is pending", func(t *testing.T) {
+		background := NewBackground(1)
+		var runs atomic.Int32
+		stop := background.Every(5*time.Millisecond, func() {
+			runs.Add(1)
+			time.Sleep(50 * time.Millisecond)
+		})
+		time.Sleep(120 * time.Millisecond)
+		stop()
+		assert.LessOrEqual(t, runs.Load(), int32(3))
+	})
+
+	t.Run("queues work beyond the cap", func(t *testing.T) {
+		background := NewBackground(1)
+		release := make(chan struct{})
+		background.Go(func() { <-release })
+		var runs atomic.Int32
+		stop := background.Every(5*time.Millisecond, func() { runs.Add(1) })
+		time.Sleep(30 * time.Millisecond)
+		assert.Zero(t, runs.Load(), "Work should wait for the busy goroutine")
+		close(release)
+		assert.Eventually(t, func() bool { return runs.Load() > 0 }, time.Second, 5*time.Millisecond)
+		stop()
+	})
+}
+
+func TestLRUCacheBackground(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+	t.Run("caches share the background goroutines", func(t *testing.T) {
+		background := NewBackground(2)
+		before := runtime.NumGoroutine()
+		caches := make([]*InMemoryLRUCache[UserData], 50)
+		for i := range caches {
+			caches[i] = cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 50, Background: background}).(*InMemoryLRUCache[UserData])
+			caches[i].Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+			caches[i].Stats()
+		}
+		assert.LessOrEqual(t, runtime.NumGoroutine(), before+2)
+
+		assert.Eventually(t, func() bool {
+			for _, cache := range caches {
+				if cache.Stats().Expirations != 1 {
+					return false
+				}
+			}
+			return true
+		}, 2*time.Second, 20*time.Millisecond, "Every cache should still be swept")
+		for _, cache := range caches {
+			assert.NoError(t, cache.Close())
+		}
+	})
+
+	t.Run("OnStats runs on the background", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, Background: NewBackground(1)}).(*InMemoryLRUCache[UserData])
+		calls := make(chan Stats, 1)
+		stop := lruCache.OnStats(10*time.Millisecond, func(stats Stats) {
+			select {
+			case calls <- stats:
+			default:
+			}
+		})
+		<-calls
+		stop()
+		assert.NoError(t, lruCache.Close())
+	})
+}
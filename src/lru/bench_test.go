@@ -0,0 +1,181 @@
+package lru
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The benchmarks below compare InMemoryLRUCache against syncMapCache, the
+// sync.Map with expiry timestamps many services start out with. Run them
+// with `make bench`, which writes the results to bench_output.txt.
+//
+// syncMapCache has no capacity limit and never removes expired entries it
+// isn't asked for again, so it is the cheapest possible TTL cache. The
+// overhead of this package buys a bounded memory footprint, recency-based
+// eviction and the rest of its features. If keys are few and bounded and
+// reads dominate, the baseline is hard to beat; with an unbounded keyspace
+// it grows without limit while the LRU cache stays at ItemLimit.
+
+type syncMapEntry[T any] struct {
+	value    T
+	deleteAt time.Time
+}
+
+type syncMapCache[T any] struct {
+	ttl     time.Duration
+	entries sync.Map
+}
+
+func (cache *syncMapCache[T]) Get(key string) (T, bool) {
+	entry, ok := cache.entries.Load(key)
+	if !ok || time.Now().After(entry.(*syncMapEntry[T]).deleteAt) {
+		var zero T
+		return zero, false
+	}
+	return entry.(*syncMapEntry[T]).value, true
+}
+
+func (cache *syncMapCache[T]) Set(key string, value T) {
+	cache.entries.Store(key, &syncMapEntry[T]{value: value, deleteAt: time.Now().Add(cache.ttl)})
+}
+
+type benchCache interface {
+	Get(key string) (UserData, bool)
+	Set(key string, value UserData)
+}
+
+type lruBenchCache struct {
+	cache *InMemoryLRUCache[UserData]
+}
+
+func (cache lruBenchCache) Get(key string) (UserData, bool) {
+	return cache.cache.GetOK(key)
+}
+
+func (cache lruBenchCache) Set(key string, value UserData) {
+	cache.cache.Set(key, value)
+}
+
+type benchWorkload struct {
+	name string
+	// keys is the keyspace, capacity the ItemLimit of the LRU cache
+	keys     int
+	capacity int64
+	// writes is the share of operations that are Sets, on top of a Set for
+	// every read miss
+	writes float64
+	// zipf skews reads towards few hot keys, uniform otherwise
+	zipf bool
+}
+
+var benchWorkloads = []benchWorkload{
+	{name: "read-heavy hot set", keys: 10_000, capacity: 10_000, writes: 0.05, zipf: true},
+	{name: "read-heavy uniform", keys: 10_000, capacity: 10_000, writes: 0.05},
+	{name: "write-heavy", keys: 10_000, capacity: 10_000, writes: 0.5, zipf: true},
+	{name: "keyspace 10x capacity", keys: 100_000, capacity: 10_000, writes: 0.05, zipf: true},
+}
+
+func BenchmarkCacheVsSyncMap(b *testing.B) {
+	logger := slog.New(slog.DiscardHandler)
+	for _, workload := range benchWorkloads {
+		keys := make([]string, workload.keys)
+		for i := range keys {
+			keys[i] = fmt.Sprintf("user%d", i)
+		}
+		caches := map[string]func() benchCache{
+			"lru": func() benchCache {
+				config := LRUCacheConfig{ItemLimit: workload.capacity, TTL: 60_000, Logger: logger}
+				return lruBenchCache{NewLRUCache[string, UserData](config)}
+			},
+			"syncmap": func() benchCache {
+				return &syncMapCache[UserData]{ttl: time.Minute}
+			},
+		}
+		for _, name := range []string{"lru", "syncmap"} {
+			b.Run(fmt.Sprintf("%s/%s", workload.name, name), func(b *testing.B) {
+				cache := caches[name]()
+				for _, key := range keys[:workload.capacity] {
+					cache.Set(key, UserData{})
+				}
+				b.ReportAllocs()
+				b.ResetTimer()
+				var seed sync.Mutex
+				next := uint64(0)
+				b.RunParallel(func(pb *testing.PB) {
+					seed.Lock()
+					next++
+					random := rand.New(rand.NewPCG(next, 0))
+					seed.Unlock()
+					pick := func() int { return random.IntN(len(keys)) }
+					if workload.zipf {
+						zipf := rand.NewZipf(random, 1.1, 1, uint64(len(keys)-1))
+						pick = func() int { return int(zipf.Uint64()) }
+					}
+					for pb.Next() {
+						key := keys[pick()]
+						if random.Float64() < workload.writes {
+							cache.Set(key, UserData{ID: 1})
+							continue
+						}
+						if _, ok := cache.Get(key); !ok {
+							cache.Set(key, UserData{ID: 1})
+						}
+					}
+				})
+			})
+		}
+	}
+}
+
+// BenchmarkCacheVsSyncMapScan reads a hot set of 200 keys round-robin while
+// a scan inserts 5 keys per read that are never read again. The hot set has
+// been read twice before, so 2Q keeps it in its main queue; plain LRU sees
+// more than ItemLimit distinct keys between two reads of a hot key and
+// misses every time.
+func BenchmarkCacheVsSyncMapScan(b *testing.B) {
+	logger := slog.New(slog.DiscardHandler)
+	for _, eviction := range []EvictionPolicy{LRUEviction, TwoQueueEviction} {
+		b.Run(fmt.Sprintf("lru/%s", eviction), func(b *testing.B) {
+			config := LRUCacheConfig{ItemLimit: 1000, TTL: 60_000, Eviction: eviction, Logger: logger}
+			benchmarkScan(b, lruBenchCache{NewLRUCache[string, UserData](config)}, 1000)
+		})
+	}
+	b.Run("syncmap", func(b *testing.B) {
+		benchmarkScan(b, &syncMapCache[UserData]{ttl: time.Minute}, 1000)
+	})
+}
+
+func benchmarkScan(b *testing.B, cache benchCache, capacity int) {
+	hot := make([]string, 200)
+	for i := range hot {
+		hot[i] = fmt.Sprintf("hot%d", i)
+		cache.Set(hot[i], UserData{})
+	}
+	for i := 0; i < capacity; i++ {
+		cache.Set(fmt.Sprintf("filler%d", i), UserData{})
+	}
+	for _, key := range hot {
+		if _, ok := cache.Get(key); !ok {
+			cache.Set(key, UserData{})
+		}
+	}
+
+	misses := 0
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key := hot[i%len(hot)]
+		if _, ok := cache.Get(key); !ok {
+			misses++
+			cache.Set(key, UserData{})
+		}
+		for j := 0; j < 5; j++ {
+			cache.Set(fmt.Sprintf("scan%d-%d", i, j), UserData{ID: i})
+		}
+	}
+	b.ReportMetric(float64(misses)/float64(b.N), "misses/op")
+}
@@ -0,0 +1,216 @@
+package lru
+
+import (
+	"sync"
+	"time"
+)
+
+// BucketedLRUCacheProvider groups entries into buckets by the time they
+// were written, and drops a whole bucket at once when it expires, without
+// visiting its entries. It suits workloads where every entry has the same
+// TTL and is rarely read back, such as deduplicating telemetry.
+//
+// Expiry is only as precise as the buckets are wide: entries live for at
+// least TTL and at most TTL plus Bucket. Reads don't extend TTLs, so
+// TTLMode, IdleTTL and MaxLifetime don't apply. Without a TTL, buckets are
+// only dropped for ItemLimit, oldest first, though never the bucket being
+// written to, so the cache can go over ItemLimit by up to that bucket.
+// There is no sweeper: expired buckets are dropped by the next call.
+//
+// Reads don't move entries between buckets either, so despite the name,
+// entries leave in write order rather than least recently used first. Has
+// and Get look key up in every bucket, newest first, so a miss costs one
+// map lookup per live bucket: about TTL over Bucket plus one.
+type BucketedLRUCacheProvider[T any] struct {
+	// Bucket is the width of a bucket, a tenth of TTL if zero, or five
+	// minutes without a TTL.
+	Bucket time.Duration
+}
+
+func (cacheProvider BucketedLRUCacheProvider[T]) NewLRUCache(config LRUCacheConfig) LRUCacher[T] {
+	width := cacheProvider.Bucket
+	ttl := time.Duration(config.TTL) * time.Millisecond
+	if width <= 0 {
+		width = ttl / 10
+	}
+	if width <= 0 {
+		width = 5 * time.Minute
+	}
+	cache := &BucketedLRUCache[T]{config: config, width: width, ttl: ttl}
+	cache.stats.createdAt = time.Now()
+	cache.stats.background = config.Background
+	if config.ExpvarName != "" {
+		PublishExpvar(config.ExpvarName, cache)
+	}
+	return cache
+}
+
+type BucketedLRUCache[T any] struct {
+	config LRUCacheConfig
+	width  time.Duration
+	// zero for no expiry
+	ttl       time.Duration
+	stats     cacheStats
+	closeOnce sync.Once
+	// time.Now unless a test sets it, see now
+	clock func() time.Time
+
+	mu sync.Mutex
+	// oldest first, each key in at most one of them
+	buckets []*timeBucket[T]
+	// entries in all buckets
+	count int
+}
+
+// now is the time entries are written and expired at.
+func (cache *BucketedLRUCache[T]) now() time.Time {
+	if cache.clock != nil {
+		return cache.clock()
+	}
+	return time.Now()
+}
+
+type timeBucket[T any] struct {
+	start   time.Time
+	entries map[string]T
+}
+
+// expire drops the buckets that expired by now. Callers must hold the lock.
+func (cache *BucketedLRUCache[T]) expire(now time.Time) {
+	for len(cache.buckets) > 0 && cache.ttl > 0 && !now.Before(cache.buckets[0].start.Add(cache.width+cache.ttl)) {
+		cache.stats.expirations.Add(uint64(cache.drop()))
+	}
+}
+
+// current returns the bucket to write to at now. Callers must hold the lock.
+func (cache *BucketedLRUCache[T]) current(now time.Time) *timeBucket[T] {
+	start := now.Truncate(cache.width)
+	if n := len(cache.buckets); n > 0 && !cache.buckets[n-1].start.Before(start) {
+		// also when the clock went back
+		return cache.buckets[n-1]
+	}
+	current := &timeBucket[T]{start: start, entries: make(map[string]T)}
+	cache.buckets = append(cache.buckets, current)
+	return current
+}
+
+// drop removes the oldest bucket and returns the number of entries it held.
+// Callers must hold the lock.
+func (cache *BucketedLRUCache[T]) drop() int {
+	n := len(cache.buckets[0].entries)
+	cache.buckets[0] = nil
+	cache.buckets = cache.buckets[1:]
+	cache.count -= n
+	return n
+}
+
+// lookup returns the bucket holding key, if any. Callers must hold the lock
+// and have expired the buckets.
+func (cache *BucketedLRUCache[T]) lookup(key string) (*timeBucket[T], T, bool) {
+	for i := len(cache.buckets) - 1; i >= 0; i-- {
+		if value, exists := cache.buckets[i].entries[key]; exists {
+			return cache.buckets[i], value, true
+		}
+	}
+	var zero T
+	return nil, zero, false
+}
+
+func (cache *BucketedLRUCache[T]) Has(key string) bool {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.expire(cache.now())
+	_, _, exists := cache.lookup(key)
+	return exists
+}
+
+func (cache *BucketedLRUCache[T]) Get(key string) (T, error) {
+	value, ok := cache.GetOK(key)
+	if !ok {
+		return value, ErrKeyNotFound
+	}
+	return value, nil
+}
+
+func (cache *BucketedLRUCache[T]) GetOK(key string) (T, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.expire(cache.now())
+	_, value, exists := cache.lookup(key)
+	if !exists {
+		cache.stats.misses.Add(1)
+		return value, false
+	}
+	cache.stats.hits.Add(1)
+	return value, true
+}
+
+// Set moves key to the current bucket, restarting its TTL.
+func (cache *BucketedLRUCache[T]) Set(key string, value T) T {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	now := cache.now()
+	cache.expire(now)
+	if bucket, _, exists := cache.lookup(key); exists {
+		delete(bucket.entries, key)
+		cache.count--
+		cache.stats.replacements.Add(1)
+	}
+	cache.current(now).entries[key] = value
+	cache.count++
+	cache.stats.sets.Add(1)
+	for limit := cache.config.ItemLimit; limit > 0 && int64(cache.count) > limit && len(cache.buckets) > 1; {
+		cache.stats.evictions.Add(uint64(cache.drop()))
+	}
+	return value
+}
+
+func (cache *BucketedLRUCache[T]) Delete(key string) bool {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.expire(cache.now())
+	bucket, _, exists := cache.lookup(key)
+	if !exists {
+		return false
+	}
+	delete(bucket.entries, key)
+	cache.count--
+	cache.stats.deletions.Add(1)
+	return true
+}
+
+func (cache *BucketedLRUCache[T]) Clear() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.expire(cache.now())
+	cache.stats.cleared.Add(uint64(cache.count))
+	cache.buckets = nil
+	cache.count = 0
+}
+
+func (cache *BucketedLRUCache[T]) Len() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.expire(cache.now())
+	return cache.count
+}
+
+func (cache *BucketedLRUCache[T]) Cap() int64 {
+	return cache.config.ItemLimit
+}
+
+func (cache *BucketedLRUCache[T]) Stats() Stats {
+	return cache.stats.snapshot()
+}
+
+// StatsDelta is like Stats, but the counters only cover what happened since
+// the previous StatsDelta call.
+func (cache *BucketedLRUCache[T]) StatsDelta() Stats {
+	return cache.stats.delta()
+}
+
+// Close stops the stats sampler. The cache stays usable afterwards.
+func (cache *BucketedLRUCache[T]) Close() error {
+	cache.closeOnce.Do(cache.stats.close)
+	return nil
+}
@@ -0,0 +1,84 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketedLRUCache(t *testing.T) {
+	newCache := func(config LRUCacheConfig, bucket time.Duration) *BucketedLRUCache[UserData] {
+		return BucketedLRUCacheProvider[UserData]{Bucket: bucket}.NewLRUCache(config).(*BucketedLRUCache[UserData])
+	}
+	alice := UserData{ID: 1, Name: "Alice", Age: 30}
+	bob := UserData{ID: 2, Name: "Bob", Age: 25}
+
+	t.Run("stores and returns values", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{TTL: 60_000}, 0)
+		assert.False(t, lruCache.Has("user1"))
+		_, err := lruCache.Get("user1")
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", bob)
+		lruCache.Set("user2", alice)
+		value, ok := lruCache.GetOK("user2")
+		assert.True(t, ok)
+		assert.Equal(t, alice, value)
+		assert.Equal(t, 2, lruCache.Len())
+
+		assert.True(t, lruCache.Delete("user1"))
+		assert.False(t, lruCache.Delete("user1"))
+		lruCache.Clear()
+		assert.Equal(t, 0, lruCache.Len())
+
+		stats := lruCache.Stats()
+		assert.Equal(t, uint64(1), stats.Hits)
+		assert.Equal(t, uint64(1), stats.Misses)
+		assert.Equal(t, uint64(1), stats.Replacements)
+		assert.Equal(t, uint64(1), stats.Deletions)
+		assert.Equal(t, uint64(1), stats.Cleared)
+	})
+
+	t.Run("drops whole buckets once they expire", func(t *testing.T) {
+		clock := newFakeClock()
+		lruCache := newCache(LRUCacheConfig{TTL: 400}, 100*time.Millisecond)
+		lruCache.clock = clock.Now
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", alice)
+		lruCache.Set("user3", alice)
+		clock.Advance(300 * time.Millisecond)
+		lruCache.Set("user3", bob)
+		lruCache.Set("user4", bob)
+		clock.Advance(250 * time.Millisecond)
+
+		assert.False(t, lruCache.Has("user1"))
+		assert.True(t, lruCache.Has("user3"), "Set should restart the TTL")
+		assert.True(t, lruCache.Has("user4"))
+		assert.Equal(t, 2, lruCache.Len())
+		assert.Equal(t, uint64(2), lruCache.Stats().Expirations)
+	})
+
+	t.Run("drops the oldest buckets for ItemLimit", func(t *testing.T) {
+		clock := newFakeClock()
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 2}, 100*time.Millisecond)
+		lruCache.clock = clock.Now
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", alice)
+		clock.Advance(100 * time.Millisecond)
+		lruCache.Set("user3", bob)
+		assert.Equal(t, 1, lruCache.Len())
+		assert.Equal(t, uint64(2), lruCache.Stats().Evictions)
+
+		lruCache.Set("user4", bob)
+		lruCache.Set("user5", bob)
+		assert.Equal(t, 3, lruCache.Len(), "The bucket being written to is never dropped")
+	})
+
+	t.Run("sizes buckets after the TTL", func(t *testing.T) {
+		assert.Equal(t, 100*time.Millisecond, newCache(LRUCacheConfig{TTL: 1000}, 0).width)
+		assert.Equal(t, 5*time.Minute, newCache(LRUCacheConfig{}, 0).width)
+		assert.Equal(t, time.Second, newCache(LRUCacheConfig{TTL: 1000}, time.Second).width)
+	})
+}
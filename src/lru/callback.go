@@ -0,0 +1,94 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrCallbackPanic is returned for hooks that panicked under
+	// Config.RecoverCallbackPanics.
+	ErrCallbackPanic = errors.New("LRU cache callback panicked")
+	// ErrCallbackTimeout is returned for hooks still running after
+	// Config.CallbackTimeout.
+	ErrCallbackTimeout = errors.New("LRU cache callback timed out")
+)
+
+// guard runs the hook named hook, isolated as set up by
+// Config.CallbackTimeout and Config.RecoverCallbackPanics. It returns nil
+// if the hook returned in time; only then may the caller read what the
+// hook wrote. It must be called without holding the lock, except for
+// Hooks.OnBeforeEvict.
+func (cache *LRUCache[K, V]) guard(hook string, fn func()) error {
+	if cache.Config.CallbackTimeout <= 0 {
+		if !cache.Config.RecoverCallbackPanics {
+			fn()
+			return nil
+		}
+		return cache.recoverPanic(hook, fn)
+	}
+	// unbuffered, so that a hook panicking right as the timer fires is
+	// either received here or reported by its own goroutine
+	done := make(chan hookOutcome)
+	abandoned := make(chan struct{})
+	go func() {
+		outcome := hookOutcome{panicked: true}
+		defer func() {
+			if outcome.panicked {
+				outcome.recovered = recover()
+			}
+			select {
+			case done <- outcome:
+			case <-abandoned:
+				if outcome.panicked {
+					cache.reportPanic(hook, outcome.recovered)
+				}
+			}
+		}()
+		fn()
+		outcome.panicked = false
+	}()
+	timer := time.NewTimer(time.Duration(cache.Config.CallbackTimeout) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case outcome := <-done:
+		if !outcome.panicked {
+			return nil
+		}
+		if !cache.Config.RecoverCallbackPanics {
+			panic(outcome.recovered)
+		}
+		return cache.reportPanic(hook, outcome.recovered)
+	case <-timer.C:
+		close(abandoned)
+		cache.stats.slowCallbacks.Add(1)
+		err := fmt.Errorf("%s: %w", hook, ErrCallbackTimeout)
+		cache.logCallbackFailure(hook, err)
+		return err
+	}
+}
+
+// hookOutcome is how a hook run by guard on its own goroutine ended.
+type hookOutcome struct {
+	panicked  bool
+	recovered any
+}
+
+func (cache *LRUCache[K, V]) recoverPanic(hook string, fn func()) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = cache.reportPanic(hook, recovered)
+		}
+	}()
+	fn()
+	return nil
+}
+
+// reportPanic counts and logs a recovered panic, and returns it as an error.
+func (cache *LRUCache[K, V]) reportPanic(hook string, recovered any) error {
+	cache.stats.callbackPanics.Add(1)
+	err := fmt.Errorf("%s: %w: %v", hook, ErrCallbackPanic, recovered)
+	cache.logCallbackFailure(hook, err)
+	return err
+}
@@ -0,0 +1,104 @@
+package lru
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheCallbackGuard(t *testing.T) {
+	alice := UserData{ID: 1, Name: "Alice", Age: 30}
+	bob := UserData{ID: 2, Name: "Bob", Age: 25}
+	newCache := func(config LRUCacheConfig, hooks Hooks[string, UserData]) *InMemoryLRUCache[UserData] {
+		return InMemoryLRUCacheProvider[UserData]{Hooks: hooks}.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+	}
+
+	t.Run("recovers panicking hooks", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1, RecoverCallbackPanics: true}, Hooks[string, UserData]{
+			OnEvict: func(key string, value UserData, reason EvictionReason) { panic("boom") },
+		})
+		lruCache.Set("user1", alice)
+		assert.NotPanics(t, func() { lruCache.Set("user2", bob) })
+		assert.True(t, lruCache.Has("user2"))
+		assert.Equal(t, uint64(1), lruCache.Stats().CallbackPanics)
+	})
+
+	t.Run("rejects values when Validate panics", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1, ValidateOnSet: true, RecoverCallbackPanics: true}, Hooks[string, UserData]{
+			Validate: func(key string, value UserData) error { panic("boom") },
+		})
+		lruCache.Set("user1", alice)
+		assert.False(t, lruCache.Has("user1"))
+		assert.Equal(t, uint64(1), lruCache.Stats().Rejections)
+	})
+
+	t.Run("stops waiting for slow hooks", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1, CallbackTimeout: 20}, Hooks[string, UserData]{
+			OnEvict: func(key string, value UserData, reason EvictionReason) { <-release },
+		})
+		lruCache.Set("user1", alice)
+		start := time.Now()
+		lruCache.Set("user2", bob)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, uint64(1), lruCache.Stats().SlowCallbacks)
+	})
+
+	t.Run("keeps the cached value when Revalidate is slow", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1, RevalidateAfter: 1, CallbackTimeout: 20}, Hooks[string, UserData]{
+			Revalidate: func(key string, value UserData) (UserData, bool, error) {
+				<-release
+				return bob, true, nil
+			},
+		})
+		lruCache.Set("user1", alice)
+		time.Sleep(10 * time.Millisecond)
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, alice, value)
+		stats := lruCache.Stats()
+		assert.Equal(t, uint64(1), stats.ErrorFallbacks)
+		assert.Equal(t, uint64(1), stats.SlowCallbacks)
+	})
+
+	t.Run("leaves panics alone by default", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1}, Hooks[string, UserData]{
+			OnEvict: func(key string, value UserData, reason EvictionReason) { panic("boom") },
+		})
+		lruCache.Set("user1", alice)
+		assert.Panics(t, func() { lruCache.Set("user2", bob) })
+	})
+
+	t.Run("panics on the caller's goroutine with a timeout", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1, CallbackTimeout: 1000}, Hooks[string, UserData]{
+			OnEvict: func(key string, value UserData, reason EvictionReason) { panic("boom") },
+		})
+		lruCache.Set("user1", alice)
+		assert.PanicsWithValue(t, "boom", func() { lruCache.Set("user2", bob) })
+
+		lruCache = newCache(LRUCacheConfig{CallbackTimeout: 20}, Hooks[string, UserData]{})
+		release := make(chan struct{})
+		assert.ErrorIs(t, lruCache.guard("OnEvict", func() {
+			<-release
+			panic("boom")
+		}), ErrCallbackTimeout)
+		close(release)
+		assert.Eventually(t, func() bool { return lruCache.Stats().CallbackPanics == 1 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("tells panics and timeouts apart", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{CallbackTimeout: 20, RecoverCallbackPanics: true}, Hooks[string, UserData]{})
+		err := lruCache.guard("OnEvict", func() { panic(errors.New("boom")) })
+		assert.ErrorIs(t, err, ErrCallbackPanic)
+		assert.ErrorContains(t, err, "boom")
+		release := make(chan struct{})
+		defer close(release)
+		assert.ErrorIs(t, lruCache.guard("OnEvict", func() { <-release }), ErrCallbackTimeout)
+		assert.NoError(t, lruCache.guard("OnEvict", func() {}))
+	})
+}
@@ -0,0 +1,70 @@
+package lru
+
+import (
+	"io"
+	"sync"
+	"unique"
+)
+
+type DedupLRUCacheProvider[T comparable] struct{}
+
+func (cacheProvider DedupLRUCacheProvider[T]) NewLRUCache(config LRUCacheConfig) LRUCacher[T] {
+	return &DedupLRUCache[T]{Cache: InMemoryLRUCacheProvider[unique.Handle[T]]{}.NewLRUCache(config)}
+}
+
+// DedupLRUCache stores each distinct value once: keys hold a unique.Handle
+// pointing at the canonical copy, which the runtime reclaims once no key
+// references it anymore.
+// The zero value is ready to use and backed by an unlimited in-memory cache.
+type DedupLRUCache[T comparable] struct {
+	Cache    LRUCacher[unique.Handle[T]]
+	initOnce sync.Once
+}
+
+func (cache *DedupLRUCache[T]) init() {
+	cache.initOnce.Do(func() {
+		if cache.Cache == nil {
+			cache.Cache = &InMemoryLRUCache[unique.Handle[T]]{}
+		}
+	})
+}
+
+func (cache *DedupLRUCache[T]) Has(key string) bool {
+	cache.init()
+	return cache.Cache.Has(key)
+}
+
+func (cache *DedupLRUCache[T]) Get(key string) (T, error) {
+	cache.init()
+	handle, err := cache.Cache.Get(key)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return handle.Value(), nil
+}
+
+func (cache *DedupLRUCache[T]) Set(key string, value T) T {
+	cache.init()
+	cache.Cache.Set(key, unique.Make(value))
+	return value
+}
+
+func (cache *DedupLRUCache[T]) Delete(key string) bool {
+	cache.init()
+	return cache.Cache.Delete(key)
+}
+
+func (cache *DedupLRUCache[T]) Clear() {
+	cache.init()
+	cache.Cache.Clear()
+}
+
+// Close closes the underlying cache if it is an io.Closer.
+func (cache *DedupLRUCache[T]) Close() error {
+	cache.init()
+	if closer, ok := cache.Cache.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
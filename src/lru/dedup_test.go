@@ -0,0 +1,46 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupLRUCache(t *testing.T) {
+	cacheProvider := DedupLRUCacheProvider[UserData]{}
+
+	t.Run("stores and returns values", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000})
+		assert.False(t, lruCache.Has("user1"))
+
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		assert.True(t, lruCache.Has("user1"))
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value)
+
+		value, err = lruCache.Get("user2")
+		assert.Error(t, err)
+		assert.Empty(t, value)
+
+		assert.True(t, lruCache.Delete("user1"))
+		assert.False(t, lruCache.Has("user1"))
+
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+		lruCache.Clear()
+		assert.False(t, lruCache.Has("user2"))
+	})
+
+	t.Run("keys with identical values share storage", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000}).(*DedupLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Set("alice", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+
+		first, _ := lruCache.Cache.Get("user1")
+		second, _ := lruCache.Cache.Get("alice")
+		third, _ := lruCache.Cache.Get("user2")
+		assert.Equal(t, first, second, "Identical values should resolve to the same handle")
+		assert.NotEqual(t, first, third)
+	})
+}
@@ -0,0 +1,35 @@
+package lru
+
+import (
+	"expvar"
+	"sync"
+)
+
+// StatsSource is implemented by InMemoryLRUCache and ShardedLRUCache.
+type StatsSource interface {
+	Stats() Stats
+}
+
+var (
+	expvarMu      sync.Mutex
+	expvarSources = make(map[string]StatsSource)
+)
+
+// PublishExpvar publishes the stats of source under expvar as
+// "lru.cache.<name>", so they show up on /debug/vars as e.g.
+// lru.cache.sessions.hits. Publishing another cache under the same name
+// replaces the previous one, which expvar alone doesn't allow; published
+// caches are never garbage collected.
+func PublishExpvar(name string, source StatsSource) {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	if _, published := expvarSources[name]; !published {
+		expvar.Publish("lru.cache."+name, expvar.Func(func() any {
+			expvarMu.Lock()
+			source := expvarSources[name]
+			expvarMu.Unlock()
+			return source.Stats()
+		}))
+	}
+	expvarSources[name] = source
+}
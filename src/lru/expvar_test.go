@@ -0,0 +1,42 @@
+package lru
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	published := func(name string) map[string]any {
+		vars := map[string]any{}
+		assert.NoError(t, json.Unmarshal([]byte(expvar.Get("lru.cache."+name).String()), &vars))
+		return vars
+	}
+
+	t.Run("publishes stats from the config", func(t *testing.T) {
+		lruCache := InMemoryLRUCacheProvider[UserData]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 10, ExpvarName: "expvar-test"})
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Get("user1")
+		lruCache.Get("user2")
+		vars := published("expvar-test")
+		assert.Equal(t, 1.0, vars["hits"])
+		assert.Equal(t, 1.0, vars["misses"])
+	})
+
+	t.Run("replaces caches published under the same name", func(t *testing.T) {
+		lruCache := InMemoryLRUCacheProvider[UserData]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		PublishExpvar("expvar-test", lruCache.(*InMemoryLRUCache[UserData]))
+		assert.Equal(t, 0.0, published("expvar-test")["hits"])
+	})
+
+	t.Run("publishes sharded caches as a whole", func(t *testing.T) {
+		lruCache := ShardedLRUCacheProvider[UserData]{Shards: 4}.NewLRUCache(LRUCacheConfig{ItemLimit: 100, ExpvarName: "expvar-sharded"})
+		for _, key := range []string{"user1", "user2", "user3", "user4", "user5"} {
+			lruCache.Set(key, UserData{ID: 1})
+			lruCache.Get(key)
+		}
+		assert.Equal(t, 5.0, published("expvar-sharded")["hits"])
+	})
+}
@@ -0,0 +1,86 @@
+package geoip
+
+import (
+	"context"
+	"net/netip"
+	"slices"
+	"sync"
+
+	"lru"
+)
+
+// Resolver looks up addr and reports the network block the result applies
+// to, e.g. the CIDR range of a GeoIP database record.
+type Resolver[T any] func(ctx context.Context, addr netip.Addr) (T, netip.Prefix, error)
+
+// Cache stores lookup results per network block, so every address inside a
+// block that has been resolved once is served from the cache.
+type Cache[T any] struct {
+	resolve Resolver[T]
+	cache   lru.LRUCacher[T]
+	mu      sync.RWMutex
+	// prefix lengths seen so far, per family, most specific first
+	lengths4 []int
+	lengths6 []int
+}
+
+func New[T any](config lru.LRUCacheConfig, resolve Resolver[T]) *Cache[T] {
+	return &Cache[T]{resolve: resolve, cache: lru.InMemoryLRUCacheProvider[T]{}.NewLRUCache(config)}
+}
+
+func (cache *Cache[T]) Lookup(ctx context.Context, addr netip.Addr) (T, error) {
+	addr = addr.Unmap()
+	for _, bits := range cache.lengths(addr.Is4()) {
+		prefix, err := addr.Prefix(bits)
+		if err != nil {
+			continue
+		}
+		if value, err := cache.cache.Get(prefix.String()); err == nil {
+			return value, nil
+		}
+	}
+
+	value, prefix, err := cache.resolve(ctx, addr)
+	if err != nil {
+		return value, err
+	}
+	prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-unmappedBits(prefix)).Masked()
+	if !prefix.IsValid() || !prefix.Contains(addr) {
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	cache.addLength(addr.Is4(), prefix.Bits())
+	cache.cache.Set(prefix.String(), value)
+	return value, nil
+}
+
+func unmappedBits(prefix netip.Prefix) int {
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		return 96
+	}
+	return 0
+}
+
+func (cache *Cache[T]) lengths(is4 bool) []int {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	if is4 {
+		return cache.lengths4
+	}
+	return cache.lengths6
+}
+
+func (cache *Cache[T]) addLength(is4 bool, bits int) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	lengths := &cache.lengths6
+	if is4 {
+		lengths = &cache.lengths4
+	}
+	if slices.Contains(*lengths, bits) {
+		return
+	}
+	// copy on write: readers may still be iterating the old slice
+	updated := append(slices.Clone(*lengths), bits)
+	slices.SortFunc(updated, func(a, b int) int { return b - a })
+	*lengths = updated
+}
@@ -0,0 +1,74 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"lru"
+)
+
+func TestCache(t *testing.T) {
+	config := lru.LRUCacheConfig{ItemLimit: 100, TTL: 10000}
+
+	t.Run("addresses in a resolved block share one entry", func(t *testing.T) {
+		calls := 0
+		cache := New(config, func(ctx context.Context, addr netip.Addr) (string, netip.Prefix, error) {
+			calls++
+			return "NL", netip.MustParsePrefix("192.0.2.0/24"), nil
+		})
+
+		for _, ip := range []string{"192.0.2.1", "192.0.2.200", "::ffff:192.0.2.7"} {
+			country, err := cache.Lookup(context.Background(), netip.MustParseAddr(ip))
+			assert.NoError(t, err)
+			assert.Equal(t, "NL", country)
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("prefers the most specific cached block", func(t *testing.T) {
+		cache := New(config, func(ctx context.Context, addr netip.Addr) (string, netip.Prefix, error) {
+			if netip.MustParsePrefix("198.51.100.128/25").Contains(addr) {
+				return "DE", netip.MustParsePrefix("198.51.100.128/25"), nil
+			}
+			return "FR", netip.MustParsePrefix("198.51.0.0/16"), nil
+		})
+
+		cache.Lookup(context.Background(), netip.MustParseAddr("198.51.100.129"))
+		cache.Lookup(context.Background(), netip.MustParseAddr("198.51.1.1"))
+
+		country, _ := cache.Lookup(context.Background(), netip.MustParseAddr("198.51.100.250"))
+		assert.Equal(t, "DE", country)
+		country, _ = cache.Lookup(context.Background(), netip.MustParseAddr("198.51.100.1"))
+		assert.Equal(t, "FR", country)
+	})
+
+	t.Run("falls back to a host entry when the block doesn't contain the address", func(t *testing.T) {
+		calls := 0
+		cache := New(config, func(ctx context.Context, addr netip.Addr) (string, netip.Prefix, error) {
+			calls++
+			return "US", netip.MustParsePrefix("203.0.113.0/24"), nil
+		})
+
+		cache.Lookup(context.Background(), netip.MustParseAddr("2001:db8::1"))
+		cache.Lookup(context.Background(), netip.MustParseAddr("2001:db8::1"))
+		cache.Lookup(context.Background(), netip.MustParseAddr("2001:db8::2"))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("does not cache resolver errors", func(t *testing.T) {
+		calls := 0
+		cache := New(config, func(ctx context.Context, addr netip.Addr) (string, netip.Prefix, error) {
+			calls++
+			return "", netip.Prefix{}, errors.New("database unavailable")
+		})
+
+		_, err := cache.Lookup(context.Background(), netip.MustParseAddr("192.0.2.1"))
+		assert.Error(t, err)
+		cache.Lookup(context.Background(), netip.MustParseAddr("192.0.2.1"))
+		assert.Equal(t, 2, calls)
+	})
+}
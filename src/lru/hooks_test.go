@@ -0,0 +1,279 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheHooks(t *testing.T) {
+	rejectNameless := func(key string, value UserData) error {
+		if value.Name == "" {
+			return errors.New("user without a name")
+		}
+		return nil
+	}
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{Validate: rejectNameless}}
+
+	t.Run("Validate rejects bad values on Set when enabled", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, ValidateOnSet: true}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value, "Rejected value should not replace the cached one")
+		assert.False(t, lruCache.Has("user2"))
+		assert.Equal(t, uint64(2), lruCache.Stats().Rejections)
+		assert.Equal(t, uint64(1), lruCache.Stats().Sets)
+	})
+
+	t.Run("Validate applies to SwapAll", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, ValidateOnSet: true}).(*InMemoryLRUCache[UserData])
+		lruCache.SwapAll(map[string]UserData{
+			"user1": {ID: 1, Name: "Alice", Age: 30},
+			"user2": {ID: 2},
+		})
+		assert.True(t, lruCache.Has("user1"))
+		assert.False(t, lruCache.Has("user2"))
+	})
+
+	t.Run("Set is not validated unless enabled", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		lruCache.Set("user2", UserData{ID: 2})
+		assert.True(t, lruCache.Has("user2"))
+	})
+}
+
+func TestLRUCacheOnEvictBatch(t *testing.T) {
+	t.Run("delivers capacity evictions", func(t *testing.T) {
+		var batches [][]Entry[string, UserData]
+		var lruCache LRUCacher[UserData]
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{OnEvictBatch: func(entries []Entry[string, UserData]) {
+			assert.False(t, lruCache.Has(entries[0].Key), "Callback should be able to use the cache")
+			batches = append(batches, entries)
+		}}}
+		lruCache = cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 1})
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+
+		assert.Equal(t, [][]Entry[string, UserData]{{{Key: "user1", Value: UserData{ID: 1, Name: "Alice", Age: 30}}}}, batches)
+	})
+
+	t.Run("delivers cleared entries in batches", func(t *testing.T) {
+		var sizes []int
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{OnEvictBatch: func(entries []Entry[string, UserData]) {
+			sizes = append(sizes, len(entries))
+		}}}
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{})
+		for i := 0; i < 2500; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+		}
+		lruCache.Clear()
+
+		assert.Equal(t, []int{1000, 1000, 500}, sizes)
+	})
+}
+
+func TestLRUCacheOnEvict(t *testing.T) {
+	type eviction struct {
+		key    string
+		reason EvictionReason
+	}
+
+	t.Run("reports capacity evictions and cleared entries", func(t *testing.T) {
+		var evictions []eviction
+		var lruCache LRUCacher[UserData]
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{OnEvict: func(key string, value UserData, reason EvictionReason) {
+			if reason != Replaced {
+				assert.False(t, lruCache.Has(key), "Callback should be able to use the cache")
+			}
+			assert.Equal(t, key, fmt.Sprintf("user%d", value.ID))
+			evictions = append(evictions, eviction{key, reason})
+		}}}
+		lruCache = cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2})
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+		lruCache.Set("user3", UserData{ID: 3})
+		lruCache.Set("user3", UserData{ID: 3})
+		assert.Equal(t, []eviction{{"user1", CapacityEvicted}, {"user3", Replaced}}, evictions)
+
+		lruCache.Delete("user3")
+		lruCache.Clear()
+		assert.Equal(t, []eviction{{"user1", CapacityEvicted}, {"user3", Replaced}, {"user3", Deleted}, {"user2", Cleared}}, evictions)
+	})
+
+	t.Run("reports expired, deleted and replaced values", func(t *testing.T) {
+		var evictions []eviction
+		var values []UserData
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{OnEvict: func(key string, value UserData, reason EvictionReason) {
+			evictions = append(evictions, eviction{key, reason})
+			values = append(values, value)
+		}}}
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user1", UserData{ID: 2})
+		assert.Equal(t, []eviction{{"user1", Replaced}}, evictions)
+		assert.Equal(t, []UserData{{ID: 1}}, values)
+
+		lruCache.Set("user2", UserData{ID: 3})
+		assert.NoError(t, lruCache.Rename("user2", "user1", true))
+		lruCache.Set("user3", UserData{ID: 4})
+		assert.Equal(t, 1, lruCache.DeletePrefix("user3"))
+		lruCache.SetWithTTL("user4", UserData{ID: 5}, 20*time.Millisecond)
+		time.Sleep(40 * time.Millisecond)
+		assert.False(t, lruCache.Delete("user4"))
+		assert.Equal(t, []eviction{{"user1", Replaced}, {"user1", Replaced}, {"user3", Deleted}, {"user4", Expired}}, evictions)
+		assert.Equal(t, []UserData{{ID: 1}, {ID: 2}, {ID: 4}, {ID: 5}}, values)
+	})
+
+	t.Run("runs along with OnEvictBatch", func(t *testing.T) {
+		var single, batched int
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{
+			OnEvict:      func(key string, value UserData, reason EvictionReason) { single++ },
+			OnEvictBatch: func(entries []Entry[string, UserData]) { batched += len(entries) },
+		}}
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 1})
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+		assert.Equal(t, 1, single)
+		assert.Equal(t, 1, batched)
+	})
+
+	t.Run("reasons have names", func(t *testing.T) {
+		assert.Equal(t, "capacity", CapacityEvicted.String())
+		assert.Equal(t, "cleared", Cleared.String())
+		assert.Equal(t, "expired", Expired.String())
+		assert.Equal(t, "deleted", Deleted.String())
+		assert.Equal(t, "replaced", Replaced.String())
+	})
+}
+
+func TestLRUCacheOnBeforeEvict(t *testing.T) {
+	newCache := func(config LRUCacheConfig, onBeforeEvict func(key string, value UserData) bool) *InMemoryLRUCache[UserData] {
+		return InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{OnBeforeEvict: onBeforeEvict}}.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+	}
+	keepOrders := func(key string, value UserData) bool { return !strings.HasPrefix(key, "order") }
+
+	t.Run("evicts the next candidate instead", func(t *testing.T) {
+		var candidates []string
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 2}, func(key string, value UserData) bool {
+			candidates = append(candidates, key)
+			return keepOrders(key, value)
+		})
+		lruCache.Set("order1", UserData{ID: 1})
+		lruCache.Set("user1", UserData{ID: 2})
+		lruCache.Set("user2", UserData{ID: 3})
+		assert.Equal(t, []string{"user2", "order1"}, lruCache.Keys())
+		assert.Equal(t, []string{"order1", "user1"}, candidates)
+		assert.Equal(t, uint64(1), lruCache.Stats().EvictionVetoes)
+		assert.Equal(t, uint64(1), lruCache.Stats().Evictions)
+	})
+
+	t.Run("works with eviction policies", func(t *testing.T) {
+		for _, eviction := range []EvictionPolicy{TwoQueueEviction, FIFOEviction, ClockEviction, SegmentedLRUEviction} {
+			lruCache := newCache(LRUCacheConfig{ItemLimit: 2, Eviction: eviction}, keepOrders)
+			lruCache.Set("order1", UserData{ID: 1})
+			lruCache.Set("user1", UserData{ID: 2})
+			lruCache.Set("user2", UserData{ID: 3})
+			lruCache.Set("user3", UserData{ID: 4})
+			assert.True(t, lruCache.Has("order1"), eviction.String())
+			assert.Equal(t, 2, lruCache.Len(), eviction.String())
+		}
+	})
+
+	t.Run("gives up after too many vetoes", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 20}, keepOrders)
+		for i := 0; i < 21; i++ {
+			lruCache.Set(fmt.Sprintf("order%d", i), UserData{ID: i})
+		}
+		assert.Equal(t, 21, lruCache.Len())
+		assert.Equal(t, uint64(maxEvictionVetoes), lruCache.Stats().EvictionVetoes)
+		assert.Equal(t, uint64(0), lruCache.Stats().Evictions)
+	})
+
+	t.Run("doesn't veto when the hook fails", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1, RecoverCallbackPanics: true, Logger: slog.New(slog.DiscardHandler)}, func(key string, value UserData) bool {
+			panic("boom")
+		})
+		lruCache.Set("order1", UserData{ID: 1})
+		lruCache.Set("user1", UserData{ID: 2})
+		assert.False(t, lruCache.Has("order1"))
+		assert.Equal(t, uint64(0), lruCache.Stats().EvictionVetoes)
+	})
+}
+
+func TestLRUCacheOnExpire(t *testing.T) {
+	newCache := func(config LRUCacheConfig, onExpire func(key string, value UserData)) LRUCacher[UserData] {
+		config.Logger = slog.New(slog.DiscardHandler)
+		return InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{OnExpire: onExpire}}.NewLRUCache(config)
+	}
+
+	t.Run("reports entries removed by the sweeper", func(t *testing.T) {
+		var mu sync.Mutex
+		var expired []string
+		var lruCache LRUCacher[UserData]
+		lruCache = newCache(LRUCacheConfig{TTL: 50, ExpiryTick: 10}, func(key string, value UserData) {
+			assert.False(t, lruCache.Has(key), "Callback should be able to use the cache")
+			mu.Lock()
+			defer mu.Unlock()
+			expired = append(expired, key)
+		})
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(expired) == 2
+		}, time.Second, 10*time.Millisecond)
+		assert.ElementsMatch(t, []string{"user1", "user2"}, expired)
+	})
+
+	t.Run("reports entries found expired on reads", func(t *testing.T) {
+		expired := make(map[string]UserData)
+		var lruCache LRUCacher[UserData]
+		// the sweeper won't get to them within the test
+		lruCache = newCache(LRUCacheConfig{TTL: 50, ExpiryTick: 10000}, func(key string, value UserData) {
+			expired[key] = value
+		})
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+		time.Sleep(100 * time.Millisecond)
+
+		_, err := lruCache.Get("user1")
+		assert.Error(t, err)
+		assert.False(t, lruCache.Has("user2"))
+		assert.Equal(t, map[string]UserData{"user1": {ID: 1}, "user2": {ID: 2}}, expired)
+	})
+
+	t.Run("reports entries replaced after expiring", func(t *testing.T) {
+		var expired []UserData
+		lruCache := newCache(LRUCacheConfig{TTL: 50, ExpiryTick: 10000}, func(key string, value UserData) {
+			expired = append(expired, value)
+		})
+		lruCache.Set("user1", UserData{ID: 1})
+		time.Sleep(100 * time.Millisecond)
+		lruCache.Set("user1", UserData{ID: 2})
+		lruCache.Set("user1", UserData{ID: 3})
+		assert.Equal(t, []UserData{{ID: 1}}, expired)
+	})
+
+	t.Run("doesn't report evicted or deleted entries", func(t *testing.T) {
+		var expired []string
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1, TTL: 50000}, func(key string, value UserData) {
+			expired = append(expired, key)
+		})
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+		lruCache.Delete("user2")
+		assert.Empty(t, expired)
+	})
+}
@@ -0,0 +1,81 @@
+package idempotency
+
+import (
+	"sync"
+
+	"lru"
+)
+
+// State is what a Store knows about an idempotency key.
+type State int
+
+const (
+	// New keys haven't been seen before, so the request should be processed.
+	New State = iota
+	// Pending keys were begun by an attempt that hasn't completed yet.
+	Pending
+	// Done keys were completed, and come with the stored result.
+	Done
+)
+
+func (state State) String() string {
+	switch state {
+	case New:
+		return "new"
+	case Pending:
+		return "pending"
+	case Done:
+		return "done"
+	}
+	return "unknown"
+}
+
+type record[T any] struct {
+	state  State
+	result T
+}
+
+// Store tracks idempotency keys so retried requests can be answered with the
+// result of the first attempt. Keys expire according to the cache config;
+// AbsoluteTTL is usually what you want here. With an ItemLimit, keys are
+// evicted like any other entry, including pending ones: a retry of a
+// request whose key was evicted while still in flight begins it again, so
+// size ItemLimit for every key that can be in flight within the TTL.
+type Store[T any] struct {
+	cache lru.LRUCacher[record[T]]
+	mu    sync.Mutex
+}
+
+func NewStore[T any](config lru.LRUCacheConfig) *Store[T] {
+	return &Store[T]{cache: lru.InMemoryLRUCacheProvider[record[T]]{}.NewLRUCache(config)}
+}
+
+// Begin returns New the first time key is seen, and marks it Pending. For
+// repeated keys it returns Pending while the first attempt is in flight,
+// and Done along with the result stored by Complete.
+func (store *Store[T]) Begin(key string) (state State, priorResult T) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	prior, err := store.cache.Get(key)
+	if err != nil {
+		store.cache.Set(key, record[T]{state: Pending})
+		return New, priorResult
+	}
+	return prior.state, prior.result
+}
+
+func (store *Store[T]) Complete(key string, result T) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.cache.Set(key, record[T]{state: Done, result: result})
+}
+
+// Abort releases key after its attempt failed, so a retry begins it again.
+// Completed keys are left alone.
+func (store *Store[T]) Abort(key string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if prior, err := store.cache.Get(key); err == nil && prior.state == Pending {
+		store.cache.Delete(key)
+	}
+}
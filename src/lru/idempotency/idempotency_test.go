@@ -0,0 +1,83 @@
+package idempotency
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"lru"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("first Begin wins and later calls see the completed result", func(t *testing.T) {
+		store := NewStore[string](lru.LRUCacheConfig{ItemLimit: 10, TTL: 1000, TTLMode: lru.AbsoluteTTL})
+
+		state, prior := store.Begin("req1")
+		assert.Equal(t, New, state)
+		assert.Empty(t, prior)
+
+		state, prior = store.Begin("req1")
+		assert.Equal(t, Pending, state, "Key 'req1' is still in flight")
+		assert.Empty(t, prior)
+
+		store.Complete("req1", "created order 42")
+		state, prior = store.Begin("req1")
+		assert.Equal(t, Done, state)
+		assert.Equal(t, "created order 42", prior)
+		assert.Equal(t, "done", state.String())
+	})
+
+	t.Run("tells zero results from attempts in flight", func(t *testing.T) {
+		store := NewStore[int](lru.LRUCacheConfig{ItemLimit: 10, TTL: 1000, TTLMode: lru.AbsoluteTTL})
+		store.Begin("req1")
+		store.Complete("req1", 0)
+		state, prior := store.Begin("req1")
+		assert.Equal(t, Done, state)
+		assert.Zero(t, prior)
+	})
+
+	t.Run("aborted keys can begin again", func(t *testing.T) {
+		store := NewStore[int](lru.LRUCacheConfig{ItemLimit: 10, TTL: 1000, TTLMode: lru.AbsoluteTTL})
+		store.Begin("req1")
+		store.Abort("req1")
+		state, _ := store.Begin("req1")
+		assert.Equal(t, New, state)
+
+		store.Complete("req1", 7)
+		store.Abort("req1")
+		state, prior := store.Begin("req1")
+		assert.Equal(t, Done, state, "Abort should leave completed keys alone")
+		assert.Equal(t, 7, prior)
+	})
+
+	t.Run("only one concurrent caller begins a key", func(t *testing.T) {
+		store := NewStore[int](lru.LRUCacheConfig{ItemLimit: 10, TTL: 1000, TTLMode: lru.AbsoluteTTL})
+		var winners atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if state, _ := store.Begin("req1"); state == New {
+					winners.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), winners.Load())
+	})
+
+	t.Run("keys can be reused once they expire", func(t *testing.T) {
+		store := NewStore[int](lru.LRUCacheConfig{ItemLimit: 10, TTL: 200, TTLMode: lru.AbsoluteTTL})
+		store.Begin("req1")
+		store.Complete("req1", 1)
+
+		time.Sleep(300 * time.Millisecond)
+		state, prior := store.Begin("req1")
+		assert.Equal(t, New, state)
+		assert.Zero(t, prior)
+	})
+}
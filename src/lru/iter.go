@@ -0,0 +1,101 @@
+package lru
+
+import (
+	"iter"
+	"slices"
+)
+
+// All yields the live entries of the cache. The entries are collected up
+// front, so the loop body may freely call back into the cache.
+func (cache *LRUCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, entry := range cache.liveEntries() {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+func (cache *LRUCache[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for _, entry := range cache.liveEntries() {
+			if !yield(entry.Key) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns the live keys, most recently used first.
+func (cache *LRUCache[K, V]) Keys() []K {
+	keys := cache.keysByRecency(false)
+	slices.Reverse(keys)
+	return keys
+}
+
+// PreviewEvictions returns the n keys that would be evicted next, in
+// eviction order, without evicting them. Expired entries the sweeper hasn't
+// removed yet are included, as eviction doesn't skip them either.
+func (cache *LRUCache[K, V]) PreviewEvictions(n int) []K {
+	if cache.frozen.Load() != nil || n <= 0 {
+		return nil
+	}
+	cache.init()
+	cache.Storage.mu.RLock()
+	if cache.policy != nil || cache.Config.Eviction == ClockEviction {
+		defer cache.Storage.mu.RUnlock()
+		if cache.policy != nil {
+			return cache.policy.victims(n)
+		}
+		return clockOrder(cache.order, n, func(key K) bool { return cache.Storage.SafeMap[key].referenced })
+	}
+	cache.Storage.mu.RUnlock()
+	keys := cache.keysByRecency(true)
+	return keys[:min(n, len(keys))]
+}
+
+// keysByRecency returns the keys least recently used first.
+func (cache *LRUCache[K, V]) keysByRecency(includeExpired bool) []K {
+	if safeMap := cache.frozen.Load(); safeMap != nil {
+		// nothing changes the order of a frozen cache
+		keys := make([]K, 0, len(*safeMap))
+		for element := cache.order.Back(); element != nil; element = element.Prev() {
+			keys = append(keys, element.Value.(K))
+		}
+		return keys
+	}
+	cache.init()
+	cache.Storage.mu.RLock()
+	defer cache.Storage.mu.RUnlock()
+	now := cache.now()
+	keys := make([]K, 0, len(cache.Storage.SafeMap))
+	for element := cache.order.Back(); element != nil; element = element.Prev() {
+		key := element.Value.(K)
+		if includeExpired || !cache.expired(cache.Storage.SafeMap[key], now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (cache *LRUCache[K, V]) liveEntries() []Entry[K, V] {
+	if safeMap := cache.frozen.Load(); safeMap != nil {
+		entries := make([]Entry[K, V], 0, len(*safeMap))
+		for key, item := range *safeMap {
+			entries = append(entries, Entry[K, V]{Key: key, Value: item.Value})
+		}
+		return entries
+	}
+	cache.init()
+	cache.Storage.mu.RLock()
+	defer cache.Storage.mu.RUnlock()
+	now := cache.now()
+	entries := make([]Entry[K, V], 0, len(cache.Storage.SafeMap))
+	for key, item := range cache.Storage.SafeMap {
+		if !cache.expired(item, now) {
+			entries = append(entries, Entry[K, V]{Key: key, Value: item.Value})
+		}
+	}
+	return entries
+}
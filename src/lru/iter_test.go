@@ -0,0 +1,90 @@
+package lru
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheIterators(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+	t.Run("All yields every live entry", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+
+		assert.Equal(t, map[string]UserData{
+			"user1": {ID: 1, Name: "Alice", Age: 30},
+			"user2": {ID: 2, Name: "Bob", Age: 25},
+		}, maps.Collect(lruCache.All()))
+	})
+
+	t.Run("KeysSeq yields every live key", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+
+		assert.ElementsMatch(t, []string{"user1", "user2"}, slices.Collect(lruCache.KeysSeq()))
+	})
+
+	t.Run("Keys lists live keys most recently used first", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 5000, IdleTTL: 100}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("expired", UserData{ID: 0})
+		time.Sleep(150 * time.Millisecond)
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+		lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+		lruCache.Get("user1")
+
+		assert.Equal(t, []string{"user1", "user3", "user2"}, lruCache.Keys())
+	})
+
+	t.Run("PreviewEvictions lists the next keys to be evicted", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 3}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+		lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+		lruCache.Get("user1")
+
+		assert.Equal(t, []string{"user2", "user3"}, lruCache.PreviewEvictions(2))
+		assert.Equal(t, []string{"user2", "user3", "user1"}, lruCache.PreviewEvictions(10))
+		assert.Equal(t, 3, lruCache.Len(), "Preview should not evict anything")
+
+		lruCache.Set("user4", UserData{ID: 4, Name: "Dave", Age: 40})
+		assert.False(t, lruCache.Has("user2"), "Previewed key should be evicted first")
+		lruCache.Freeze()
+		assert.Empty(t, lruCache.PreviewEvictions(1), "Frozen caches don't evict")
+	})
+
+	t.Run("skips expired entries and stops early", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 5000, IdleTTL: 100}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		time.Sleep(150 * time.Millisecond)
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+		lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+
+		count := 0
+		for key := range lruCache.KeysSeq() {
+			assert.NotEqual(t, "user1", key)
+			count++
+			break
+		}
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("loop body can write to the cache", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		for key, value := range lruCache.All() {
+			value.Age++
+			lruCache.Set(key, value)
+		}
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, 31, value.Age)
+	})
+}
@@ -0,0 +1,191 @@
+package lru
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// The MarshalJSON methods produce stable snake_case documents for
+// diagnostics bundles. Durations are in milliseconds, like in
+// LRUCacheConfig.
+
+// MarshalJSON leaves out Logger and SnapshotKeys, only reporting whether
+// they are set.
+func (config LRUCacheConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ItemLimit          int64   `json:"item_limit"`
+		TTL                int64   `json:"ttl_ms"`
+		IdleTTL            int64   `json:"idle_ttl_ms"`
+		MaxLifetime        int64   `json:"max_lifetime_ms"`
+		ExpiryTick         int64   `json:"expiry_tick_ms"`
+		SweepBatch         int     `json:"sweep_batch"`
+		InlineExpiryBudget int     `json:"inline_expiry_budget"`
+		PrefixIndex        bool    `json:"prefix_index"`
+		PromoteEvery       int     `json:"promote_every"`
+		PromoteInterval    int64   `json:"promote_interval_ms"`
+		TTLMode            string  `json:"ttl_mode"`
+		FrozenWrites       string  `json:"frozen_writes"`
+		WriteOnce          bool    `json:"write_once"`
+		VictimCacheSize    int64   `json:"victim_cache_size"`
+		Eviction           string  `json:"eviction"`
+		Admission          string  `json:"admission"`
+		ProbationaryRatio  float64 `json:"probationary_ratio"`
+		ProtectedRatio     float64 `json:"protected_ratio"`
+		ValidateOnSet      bool    `json:"validate_on_set"`
+		RevalidateAfter    int64   `json:"revalidate_after_ms"`
+		LoaderCooldown     int64   `json:"loader_cooldown_ms"`
+		MaxLoaderCooldown  int64   `json:"max_loader_cooldown_ms"`
+		CallbackTimeout    int64   `json:"callback_timeout_ms"`
+		RecoverPanics      bool    `json:"recover_callback_panics"`
+		ExpvarName         string  `json:"expvar_name"`
+		RedactKeys         bool    `json:"redact_keys"`
+		Background         bool    `json:"background"`
+		Logger             bool    `json:"logger"`
+		SnapshotEncryption bool    `json:"snapshot_encryption"`
+	}{
+		ItemLimit:          config.ItemLimit,
+		TTL:                config.TTL,
+		IdleTTL:            config.IdleTTL,
+		MaxLifetime:        config.MaxLifetime,
+		ExpiryTick:         config.ExpiryTick,
+		SweepBatch:         config.SweepBatch,
+		InlineExpiryBudget: config.InlineExpiryBudget,
+		PrefixIndex:        config.PrefixIndex,
+		PromoteEvery:       config.PromoteEvery,
+		PromoteInterval:    config.PromoteInterval,
+		TTLMode:            config.TTLMode.String(),
+		FrozenWrites:       config.FrozenWrites.String(),
+		WriteOnce:          config.WriteOnce,
+		VictimCacheSize:    config.VictimCacheSize,
+		Eviction:           config.Eviction.String(),
+		Admission:          config.Admission.String(),
+		ProbationaryRatio:  config.ProbationaryRatio,
+		ProtectedRatio:     config.ProtectedRatio,
+		ValidateOnSet:      config.ValidateOnSet,
+		RevalidateAfter:    config.RevalidateAfter,
+		LoaderCooldown:     config.LoaderCooldown,
+		MaxLoaderCooldown:  config.MaxLoaderCooldown,
+		CallbackTimeout:    config.CallbackTimeout,
+		RecoverPanics:      config.RecoverCallbackPanics,
+		ExpvarName:         config.ExpvarName,
+		RedactKeys:         config.RedactKeys,
+		Background:         config.Background != nil,
+		Logger:             config.Logger != nil,
+		SnapshotEncryption: config.SnapshotKeys != nil,
+	})
+}
+
+type windowStatsJSON struct {
+	HitsPerSecond   float64 `json:"hits_per_second"`
+	MissesPerSecond float64 `json:"misses_per_second"`
+	MissRatio       float64 `json:"miss_ratio"`
+}
+
+func (window WindowStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(windowStatsJSON{
+		HitsPerSecond:   window.HitsPerSecond,
+		MissesPerSecond: window.MissesPerSecond,
+		MissRatio:       window.MissRatio,
+	})
+}
+
+func (stats Stats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Hits               uint64      `json:"hits"`
+		FreshHits          uint64      `json:"fresh_hits"`
+		StaleHits          uint64      `json:"stale_hits"`
+		ErrorFallbacks     uint64      `json:"error_fallbacks"`
+		Misses             uint64      `json:"misses"`
+		Sets               uint64      `json:"sets"`
+		Fills              uint64      `json:"fills"`
+		Evictions          uint64      `json:"evictions"`
+		PrematureEvictions uint64      `json:"premature_evictions"`
+		EvictionVetoes     uint64      `json:"eviction_vetoes"`
+		Expirations        uint64      `json:"expirations"`
+		Deletions          uint64      `json:"deletions"`
+		Replacements       uint64      `json:"replacements"`
+		Cleared            uint64      `json:"cleared"`
+		Rejections         uint64      `json:"rejections"`
+		ReadOnlyRejections uint64      `json:"read_only_rejections"`
+		CallbackPanics     uint64      `json:"callback_panics"`
+		SlowCallbacks      uint64      `json:"slow_callbacks"`
+		ExpiryPaused       bool        `json:"expiry_paused"`
+		LastMinute         WindowStats `json:"last_minute"`
+		LastFiveMinutes    WindowStats `json:"last_five_minutes"`
+		LastFifteenMinutes WindowStats `json:"last_fifteen_minutes"`
+	}{
+		Hits:               stats.Hits,
+		FreshHits:          stats.FreshHits,
+		StaleHits:          stats.StaleHits,
+		ErrorFallbacks:     stats.ErrorFallbacks,
+		Misses:             stats.Misses,
+		Sets:               stats.Sets,
+		Fills:              stats.Fills,
+		Evictions:          stats.Evictions,
+		PrematureEvictions: stats.PrematureEvictions,
+		EvictionVetoes:     stats.EvictionVetoes,
+		Expirations:        stats.Expirations,
+		Deletions:          stats.Deletions,
+		Replacements:       stats.Replacements,
+		Cleared:            stats.Cleared,
+		Rejections:         stats.Rejections,
+		ReadOnlyRejections: stats.ReadOnlyRejections,
+		CallbackPanics:     stats.CallbackPanics,
+		SlowCallbacks:      stats.SlowCallbacks,
+		ExpiryPaused:       stats.ExpiryPaused,
+		LastMinute:         stats.LastMinute,
+		LastFiveMinutes:    stats.LastFiveMinutes,
+		LastFifteenMinutes: stats.LastFifteenMinutes,
+	})
+}
+
+func (provenance Provenance) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Loader       string `json:"loader,omitempty"`
+		SourceTier   int    `json:"source_tier,omitempty"`
+		Node         string `json:"node,omitempty"`
+		LoadDuration int64  `json:"load_duration_ms,omitempty"`
+	}{
+		Loader:       provenance.Loader,
+		SourceTier:   provenance.SourceTier,
+		Node:         provenance.Node,
+		LoadDuration: provenance.LoadDuration.Milliseconds(),
+	})
+}
+
+// MarshalJSON hashes the key if the cache has RedactKeys set. Timestamps
+// that weren't recorded, such as on caches without expiry, are left out.
+func (info EntryInfo) MarshalJSON() ([]byte, error) {
+	key := info.Key
+	if info.redactKey {
+		key = redactKey(key)
+	}
+	var provenance *Provenance
+	if info.Provenance != (Provenance{}) {
+		provenance = &info.Provenance
+	}
+	return json.Marshal(struct {
+		Key        string      `json:"key"`
+		InsertedAt *time.Time  `json:"inserted_at,omitempty"`
+		WrittenAt  *time.Time  `json:"written_at,omitempty"`
+		AccessedAt *time.Time  `json:"accessed_at,omitempty"`
+		ExpiresAt  *time.Time  `json:"expires_at,omitempty"`
+		Provenance *Provenance `json:"provenance,omitempty"`
+		ReadOnly   bool        `json:"read_only,omitempty"`
+	}{
+		Key:        key,
+		InsertedAt: timeOrNil(info.InsertedAt),
+		WrittenAt:  timeOrNil(info.WrittenAt),
+		AccessedAt: timeOrNil(info.AccessedAt),
+		ExpiresAt:  timeOrNil(info.ExpiresAt),
+		Provenance: provenance,
+		ReadOnly:   info.ReadOnly,
+	})
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
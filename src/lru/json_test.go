@@ -0,0 +1,68 @@
+package lru
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheJSON(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+	t.Run("config marshals without logger or key resolver internals", func(t *testing.T) {
+		data, err := json.Marshal(LRUCacheConfig{ItemLimit: 10, TTL: 1000, TTLMode: AbsoluteTTL, Logger: slog.New(slog.DiscardHandler)})
+		assert.NoError(t, err)
+
+		var doc map[string]any
+		assert.NoError(t, json.Unmarshal(data, &doc))
+		assert.Equal(t, float64(10), doc["item_limit"])
+		assert.Equal(t, float64(1000), doc["ttl_ms"])
+		assert.Equal(t, "absolute", doc["ttl_mode"])
+		assert.Equal(t, "drop", doc["frozen_writes"])
+		assert.Equal(t, true, doc["logger"])
+		assert.Equal(t, false, doc["snapshot_encryption"])
+	})
+
+	t.Run("stats marshal with snake_case counters and windows", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Get("user1")
+		lruCache.Get("user2")
+
+		data, err := json.Marshal(lruCache.Stats())
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"hits":1,"fresh_hits":1,"stale_hits":0,"error_fallbacks":0,"misses":1,"sets":1`)
+		assert.Contains(t, string(data), `"last_minute":{"hits_per_second":`)
+	})
+
+	t.Run("entry info includes the key and provenance", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.SetWithProvenance("user1", UserData{ID: 1, Name: "Alice", Age: 30}, Provenance{Loader: "db", SourceTier: 2})
+
+		info, _ := lruCache.EntryInfo("user1")
+		data, err := json.Marshal(info)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"key":"user1","provenance":{"loader":"db","source_tier":2}}`, string(data))
+	})
+
+	t.Run("redacts keys when configured to", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 1, RedactKeys: true, Logger: logger}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("alice@example.com", UserData{ID: 1, Name: "Alice", Age: 30})
+
+		info, _ := lruCache.EntryInfo("alice@example.com")
+		assert.Equal(t, "alice@example.com", info.Key)
+		data, err := json.Marshal(info)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(data), "alice@example.com")
+		assert.Contains(t, string(data), `"key":"sha256:`)
+
+		lruCache.Set("bob@example.com", UserData{ID: 2, Name: "Bob", Age: 25})
+		assert.NotContains(t, buf.String(), "alice@example.com", "Logs should not contain the key either")
+		assert.Contains(t, buf.String(), redactKey("alice@example.com"))
+	})
+}
@@ -0,0 +1,129 @@
+package lru
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"time"
+)
+
+type loadCall[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// loadFailure tracks the consecutive loader failures of a key for
+// LoaderCooldown.
+type loadFailure struct {
+	count   int
+	retryAt time.Time
+	err     error
+}
+
+// GetOrLoad returns the cached value of key, or calls loader and caches its
+// result. Concurrent misses for the same key share a single loader call.
+// Errors are returned to every waiter but not cached. Loaded values go
+// through Hooks.Validate, and are recorded as fills with the loader's name
+// as their Provenance.
+//
+// With LoaderCooldown, keys whose loader failed recently get an error
+// wrapping both ErrLoaderCooldown and the last loader error, without the
+// loader being called.
+func (cache *LRUCache[K, V]) GetOrLoad(key K, loader func(key K) (V, error)) (V, error) {
+	if value, err := cache.Get(key); err == nil {
+		return value, nil
+	}
+
+	cache.loadMu.Lock()
+	if call, exists := cache.loading[key]; exists {
+		cache.loadMu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+	if failure, exists := cache.failures[key]; exists && cache.now().Before(failure.retryAt) {
+		cache.loadMu.Unlock()
+		var zero V
+		return zero, fmt.Errorf("%w for %q until %s: %w", ErrLoaderCooldown, keyString(key), failure.retryAt.Format(time.RFC3339Nano), failure.err)
+	}
+	call := &loadCall[V]{done: make(chan struct{})}
+	if cache.loading == nil {
+		cache.loading = make(map[K]*loadCall[V])
+	}
+	cache.loading[key] = call
+	cache.loadMu.Unlock()
+
+	cache.load(key, loader, call)
+	return call.value, call.err
+}
+
+func (cache *LRUCache[K, V]) load(key K, loader func(key K) (V, error), call *loadCall[V]) {
+	defer func() {
+		cache.loadMu.Lock()
+		delete(cache.loading, key)
+		cache.recordLoad(key, call.err)
+		cache.loadMu.Unlock()
+		close(call.done)
+	}()
+	defer func() {
+		if r := recover(); r != nil {
+			call.err = fmt.Errorf("loader for %q panicked: %v", keyString(key), r)
+			panic(r)
+		}
+	}()
+
+	// another call may have stored it since our miss
+	if value, ok := cache.Peek(key); ok {
+		call.value = value
+		return
+	}
+	start := time.Now()
+	value, err := loader(key)
+	if err == nil {
+		err = cache.validate(key, value)
+	}
+	if err != nil {
+		call.err = err
+		return
+	}
+	cache.setValid(key, value, &Provenance{Loader: funcName(loader), LoadDuration: time.Since(start)}, 0)
+	call.value = value
+}
+
+// recordLoad updates the failure streak of key. Callers must hold loadMu.
+func (cache *LRUCache[K, V]) recordLoad(key K, err error) {
+	base := time.Duration(cache.Config.LoaderCooldown) * time.Millisecond
+	if base <= 0 {
+		return
+	}
+	if err == nil {
+		delete(cache.failures, key)
+		return
+	}
+	if cache.failures == nil {
+		cache.failures = make(map[K]*loadFailure)
+	}
+	failure, exists := cache.failures[key]
+	if !exists {
+		failure = &loadFailure{}
+		cache.failures[key] = failure
+	}
+	limit := time.Duration(cache.Config.MaxLoaderCooldown) * time.Millisecond
+	if limit <= 0 {
+		limit = 64 * base
+	}
+	cooldown := base
+	for i := 0; i < failure.count && cooldown < limit; i++ {
+		cooldown *= 2
+	}
+	failure.count++
+	failure.retryAt = cache.now().Add(min(cooldown, limit))
+	failure.err = err
+}
+
+func funcName(fn any) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
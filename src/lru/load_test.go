@@ -0,0 +1,175 @@
+package lru
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadUser(key string) (UserData, error) {
+	return UserData{ID: 1, Name: "Alice", Age: 30}, nil
+}
+
+func TestLRUCacheGetOrLoad(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+	t.Run("loads misses and caches the result", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		value, err := lruCache.GetOrLoad("user1", loadUser)
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value)
+
+		info, ok := lruCache.EntryInfo("user1")
+		assert.True(t, ok)
+		assert.Equal(t, "lru.loadUser", info.Provenance.Loader)
+		assert.Equal(t, uint64(1), lruCache.Stats().Fills)
+	})
+
+	t.Run("concurrent misses share one loader call", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		var calls atomic.Int32
+		loader := func(key string) (UserData, error) {
+			calls.Add(1)
+			time.Sleep(50 * time.Millisecond)
+			return UserData{ID: 1, Name: "Alice", Age: 30}, nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, err := lruCache.GetOrLoad("user1", loader)
+				assert.NoError(t, err)
+				assert.Equal(t, "Alice", value.Name)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("doesn't cache errors", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		var calls atomic.Int32
+		loader := func(key string) (UserData, error) {
+			calls.Add(1)
+			return UserData{}, errors.New("database down")
+		}
+
+		_, err := lruCache.GetOrLoad("user1", loader)
+		assert.EqualError(t, err, "database down")
+		_, err = lruCache.GetOrLoad("user1", loader)
+		assert.Error(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+		assert.False(t, lruCache.Has("user1"))
+	})
+
+	t.Run("validates loaded values", func(t *testing.T) {
+		lruCache := InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{Validate: func(key string, user UserData) error {
+			if user.Name == "" {
+				return errors.New("partial read")
+			}
+			return nil
+		}}}.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+
+		_, err := lruCache.GetOrLoad("user1", func(key string) (UserData, error) {
+			return UserData{ID: 1}, nil
+		})
+		assert.EqualError(t, err, "partial read")
+		assert.False(t, lruCache.Has("user1"), "Rejected value should not be cached")
+		assert.Equal(t, uint64(1), lruCache.Stats().Rejections)
+	})
+
+	t.Run("cools down keys whose loader keeps failing", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, LoaderCooldown: 100}).(*InMemoryLRUCache[UserData])
+		var calls atomic.Int32
+		failing := func(key string) (UserData, error) {
+			calls.Add(1)
+			return UserData{}, errors.New("database down")
+		}
+
+		_, err := lruCache.GetOrLoad("user1", failing)
+		assert.EqualError(t, err, "database down")
+		_, err = lruCache.GetOrLoad("user1", failing)
+		assert.ErrorIs(t, err, ErrLoaderCooldown)
+		assert.ErrorContains(t, err, "database down")
+		assert.Equal(t, int32(1), calls.Load(), "Loader should not run during the cooldown")
+
+		value, err := lruCache.GetOrLoad("user2", loadUser)
+		assert.NoError(t, err, "Other keys should not be affected")
+		assert.Equal(t, "Alice", value.Name)
+
+		time.Sleep(150 * time.Millisecond)
+		_, err = lruCache.GetOrLoad("user1", failing)
+		assert.EqualError(t, err, "database down")
+		assert.Equal(t, int32(2), calls.Load())
+
+		time.Sleep(150 * time.Millisecond)
+		_, err = lruCache.GetOrLoad("user1", failing)
+		assert.ErrorIs(t, err, ErrLoaderCooldown, "Second failure should double the cooldown")
+
+		time.Sleep(100 * time.Millisecond)
+		value, err = lruCache.GetOrLoad("user1", loadUser)
+		assert.NoError(t, err)
+		assert.Equal(t, "Alice", value.Name)
+	})
+
+	t.Run("resets the cooldown after a successful load", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, LoaderCooldown: 50}).(*InMemoryLRUCache[UserData])
+		failing := func(key string) (UserData, error) {
+			return UserData{}, errors.New("database down")
+		}
+
+		lruCache.GetOrLoad("user1", failing)
+		time.Sleep(100 * time.Millisecond)
+		lruCache.GetOrLoad("user1", loadUser)
+		lruCache.Delete("user1")
+
+		lruCache.GetOrLoad("user1", failing)
+		time.Sleep(100 * time.Millisecond)
+		_, err := lruCache.GetOrLoad("user1", loadUser)
+		assert.NoError(t, err, "Cooldown should restart from LoaderCooldown")
+	})
+
+	t.Run("caps the cooldown at MaxLoaderCooldown", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, LoaderCooldown: 40, MaxLoaderCooldown: 60}).(*InMemoryLRUCache[UserData])
+		failing := func(key string) (UserData, error) {
+			return UserData{}, errors.New("database down")
+		}
+
+		for i := 0; i < 4; i++ {
+			lruCache.GetOrLoad("user1", failing)
+			time.Sleep(80 * time.Millisecond)
+		}
+		_, err := lruCache.GetOrLoad("user1", loadUser)
+		assert.NoError(t, err)
+	})
+
+	t.Run("releases waiters when the loader panics", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		started := make(chan struct{})
+		release := make(chan struct{})
+		go func() {
+			defer func() { recover() }()
+			lruCache.GetOrLoad("user1", func(key string) (UserData, error) {
+				close(started)
+				<-release
+				panic("boom")
+			})
+		}()
+		<-started
+
+		errs := make(chan error)
+		go func() {
+			_, err := lruCache.GetOrLoad("user1", loadUser)
+			errs <- err
+		}()
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		assert.ErrorContains(t, <-errs, "panicked", "Waiter should get an error instead of hanging")
+	})
+}
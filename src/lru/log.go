@@ -0,0 +1,105 @@
+package lru
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// logger returns Config.Logger if it logs at level, nil otherwise. Without
+// a Logger the cache logs nothing.
+func (cache *LRUCache[K, V]) logger(level slog.Level) *slog.Logger {
+	logger := cache.Config.Logger
+	if logger == nil || !logger.Enabled(context.Background(), level) {
+		return nil
+	}
+	return logger
+}
+
+func (cache *LRUCache[K, V]) logExpiry(key K, overdue time.Duration) {
+	if logger := cache.logger(slog.LevelDebug); logger != nil {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "lru: expired key",
+			slog.String("key", cache.logKey(key)), slog.Duration("overdue", overdue))
+	}
+}
+
+func (cache *LRUCache[K, V]) logEviction(key K) {
+	if logger := cache.logger(slog.LevelDebug); logger != nil {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "lru: evicted key",
+			slog.String("key", cache.logKey(key)), slog.String("reason", "capacity"))
+	}
+}
+
+func (cache *LRUCache[K, V]) logRejection(key K, err error) {
+	if logger := cache.logger(slog.LevelDebug); logger != nil {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "lru: rejected value",
+			slog.String("key", cache.logKey(key)), slog.Any("error", err))
+	}
+}
+
+func (cache *LRUCache[K, V]) logErrorFallback(key K, err error) {
+	if logger := cache.logger(slog.LevelDebug); logger != nil {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "lru: served cached value after revalidation failed",
+			slog.String("key", cache.logKey(key)), slog.String("outcome", "error_fallback"), slog.Any("error", err))
+	}
+}
+
+func (cache *LRUCache[K, V]) logCallbackFailure(hook string, err error) {
+	if logger := cache.logger(slog.LevelWarn); logger != nil {
+		logger.LogAttrs(context.Background(), slog.LevelWarn, "lru: callback failed",
+			slog.String("hook", hook), slog.Any("error", err))
+	}
+}
+
+// keyString formats a key for logs and error messages.
+func keyString[K comparable](key K) string {
+	if s, ok := any(key).(string); ok {
+		return s
+	}
+	return fmt.Sprint(key)
+}
+
+func (cache *LRUCache[K, V]) logKey(key K) string {
+	if cache.Config.RedactKeys {
+		return redactKey(keyString(key))
+	}
+	return keyString(key)
+}
+
+// redactKey returns a stable hash of key, so redacted keys can still be
+// told apart and correlated.
+func redactKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:6])
+}
+
+// LogValue lets a Stats value be passed straight to slog, e.g.
+// logger.Info("cache stats", "stats", cache.Stats()).
+func (stats Stats) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Uint64("hits", stats.Hits),
+		slog.Uint64("misses", stats.Misses),
+		slog.Uint64("sets", stats.Sets),
+		slog.Uint64("fills", stats.Fills),
+		slog.Uint64("evictions", stats.Evictions),
+		slog.Uint64("premature_evictions", stats.PrematureEvictions),
+		slog.Uint64("eviction_vetoes", stats.EvictionVetoes),
+		slog.Uint64("expirations", stats.Expirations),
+		slog.Uint64("deletions", stats.Deletions),
+		slog.Uint64("replacements", stats.Replacements),
+		slog.Uint64("cleared", stats.Cleared),
+		slog.Uint64("rejections", stats.Rejections),
+		slog.Uint64("read_only_rejections", stats.ReadOnlyRejections),
+		slog.Uint64("callback_panics", stats.CallbackPanics),
+		slog.Uint64("slow_callbacks", stats.SlowCallbacks),
+		slog.Uint64("fresh_hits", stats.FreshHits),
+		slog.Uint64("stale_hits", stats.StaleHits),
+		slog.Uint64("error_fallbacks", stats.ErrorFallbacks),
+		slog.Bool("expiry_paused", stats.ExpiryPaused),
+		slog.Float64("hits_per_second_1m", stats.LastMinute.HitsPerSecond),
+		slog.Float64("miss_ratio_1m", stats.LastMinute.MissRatio),
+	)
+}
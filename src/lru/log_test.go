@@ -0,0 +1,66 @@
+package lru
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheLogging(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+	t.Run("logs evictions and expiries at debug level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 1, TTL: 100, Logger: logger})
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+		time.Sleep(200 * time.Millisecond)
+
+		lruCache.(*InMemoryLRUCache[UserData]).Storage.mu.Lock()
+		output := buf.String()
+		lruCache.(*InMemoryLRUCache[UserData]).Storage.mu.Unlock()
+		assert.Contains(t, output, `msg="lru: evicted key" key=user1 reason=capacity`)
+		assert.Contains(t, output, `msg="lru: expired key" key=user2`)
+	})
+
+	t.Run("stays quiet above debug level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 1, Logger: logger})
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("logs nothing without a Logger", func(t *testing.T) {
+		clock := newFakeClock()
+		lruCache := &InMemoryLRUCache[UserData]{Config: LRUCacheConfig{ItemLimit: 1, TTL: 20}, clock: clock.Now}
+		assert.NoError(t, lruCache.Close())
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+		clock.Advance(50 * time.Millisecond)
+		lruCache.sweepKeys()
+		assert.Nil(t, lruCache.logger(slog.LevelError))
+
+		var buf bytes.Buffer
+		lruCache.Config.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		lruCache.Set("user3", UserData{ID: 3})
+		lruCache.Set("user4", UserData{ID: 4})
+		assert.Contains(t, buf.String(), `msg="lru: evicted key" key=user3 reason=capacity`)
+	})
+
+	t.Run("stats log as a structured group", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Get("user1")
+
+		logger.Info("cache stats", "stats", lruCache.Stats())
+		assert.Contains(t, buf.String(), "stats.hits=1 stats.misses=0 stats.sets=1")
+	})
+}
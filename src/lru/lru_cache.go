@@ -1,113 +1,1254 @@
 package lru
 
 import (
+	"container/list"
 	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+type TTLMode int
+
+const (
+	// SlidingTTL restarts the TTL on every access.
+	SlidingTTL TTLMode = iota
+	// AbsoluteTTL expires entries TTL after they were last written.
+	AbsoluteTTL
+)
+
+func (mode TTLMode) String() string {
+	switch mode {
+	case SlidingTTL:
+		return "sliding"
+	case AbsoluteTTL:
+		return "absolute"
+	}
+	return "unknown"
+}
+
+type FrozenWritePolicy int
+
+const (
+	// DropFrozenWrites silently ignores writes to a frozen cache.
+	DropFrozenWrites FrozenWritePolicy = iota
+	// PanicOnFrozenWrites panics with ErrCacheFrozen on writes to a frozen cache.
+	PanicOnFrozenWrites
+)
+
+func (policy FrozenWritePolicy) String() string {
+	switch policy {
+	case DropFrozenWrites:
+		return "drop"
+	case PanicOnFrozenWrites:
+		return "panic"
+	}
+	return "unknown"
+}
+
+// EvictionReason tells why an entry left the cache.
+type EvictionReason int
+
+const (
+	// CapacityEvicted entries made room for another on a full cache.
+	CapacityEvicted EvictionReason = iota
+	// Cleared entries were dropped by Clear.
+	Cleared
+	// Expired entries outlived their TTL.
+	Expired
+	// Deleted entries were removed by Delete or DeletePrefix.
+	Deleted
+	// Replaced entries were overwritten with a new value, by Set, Rename or
+	// revalidation. Patch updates values in place and doesn't count.
+	Replaced
+)
+
+func (reason EvictionReason) String() string {
+	switch reason {
+	case CapacityEvicted:
+		return "capacity"
+	case Cleared:
+		return "cleared"
+	case Expired:
+		return "expired"
+	case Deleted:
+		return "deleted"
+	case Replaced:
+		return "replaced"
+	}
+	return "unknown"
+}
+
+var ErrCacheFrozen = errors.New("LRU cache is frozen")
+
+// ErrReadOnly is returned when writing to an entry set with SetReadOnly.
+var ErrReadOnly = errors.New("LRU cache entry is read-only")
+
+var (
+	// ErrKeyNotFound is returned for keys the cache doesn't hold.
+	ErrKeyNotFound = errors.New("key not found on LRU cache")
+	// ErrKeyExpired is returned instead for keys whose entry was found
+	// expired, and matches ErrKeyNotFound with errors.Is as well. Entries
+	// the sweeper removed first are just not found.
+	ErrKeyExpired = fmt.Errorf("%w: entry expired", ErrKeyNotFound)
+)
+
+// ErrLoaderCooldown is returned by GetOrLoad for keys whose loader failed
+// recently, along with the loader's last error.
+var ErrLoaderCooldown = errors.New("LRU cache loader is cooling down")
+
 type LRUCacheConfig struct {
+	// ItemLimit caps the number of entries. Zero or less means unlimited.
 	ItemLimit int64
-	TTL       int64
+	// TTL in milliseconds. Zero disables expiry; with IdleTTL also zero the
+	// cache skips all expiry bookkeeping and doesn't start a sweeper.
+	TTL int64
+	// IdleTTL expires entries that haven't been accessed for this many
+	// milliseconds, independently of TTL. Zero disables it.
+	IdleTTL int64
+	// MaxLifetime expires entries this many milliseconds after they were
+	// first inserted, however often they are read or re-set. Zero disables it.
+	MaxLifetime int64
+	// ExpiryTick is the granularity of expiry in milliseconds: entries
+	// expiring within the same tick are dropped together, up to one tick
+	// late. Zero means 50ms.
+	ExpiryTick int64
+	// SweepBatch caps how many expired entries the sweeper removes per lock
+	// acquisition, so writers wait for at most one batch. Zero means 1000.
+	SweepBatch int
+	// InlineExpiryBudget lets a Set on a full cache remove up to this many
+	// expired entries itself before evicting a live one. The sweeper handles
+	// the rest. Zero leaves all expiry to the sweeper.
+	InlineExpiryBudget int
+	// PrefixIndex keeps a trie of the keys, so prefix operations such as
+	// DeletePrefix only visit the matching keys instead of all of them.
+	// Ignored unless the keys are strings.
+	PrefixIndex bool
+	// PromoteEvery only makes an entry the most recently used one on every
+	// Nth access, and PromoteInterval at most once per this many
+	// milliseconds. Both cut down on churn for hot keys; zero promotes on
+	// every access. New entries and Sets are always promoted.
+	PromoteEvery    int
+	PromoteInterval int64
+	TTLMode         TTLMode
+	FrozenWrites    FrozenWritePolicy
+	// WriteOnce makes every entry read-only, as if set with SetReadOnly,
+	// for caches of immutable data such as content-addressed blobs. Set
+	// drops writes to live entries, counting them in
+	// Stats.ReadOnlyRejections; use TrySet to get ErrReadOnly instead. Get
+	// and Has serve live entries without taking the lock, so their hits
+	// neither promote entries nor extend TTLs: entries are evicted in write
+	// order, and expire as if TTLMode were AbsoluteTTL. With IdleTTL they
+	// take the lock after all, so that reads keep entries from idling out.
+	WriteOnce bool
+	// VictimCacheSize keeps up to this many of the entries last evicted for
+	// capacity on the side, outside ItemLimit. Reading one moves it back
+	// into the cache and counts it in Stats.PrematureEvictions. Hooks only
+	// see such entries once they leave the victim cache too. Zero disables
+	// it.
+	VictimCacheSize int64
+	// Eviction picks the entry to evict when the cache is full, and
+	// Admission whether a new entry is worth evicting it for.
+	Eviction  EvictionPolicy
+	Admission AdmissionPolicy
+	// ProbationaryRatio and ProtectedRatio split ItemLimit between the
+	// segments of SegmentedLRUEviction, in proportion to each other. Zero
+	// means 0.2 and 0.8 respectively.
+	ProbationaryRatio float64
+	ProtectedRatio    float64
+	// ValidateOnSet runs Hooks.Validate on Set and SwapAll as well.
+	ValidateOnSet bool
+	// RevalidateAfter makes Get run Hooks.Revalidate on entries that were
+	// written or last revalidated more than this many milliseconds ago.
+	// Zero disables revalidation.
+	RevalidateAfter int64
+	// LoaderCooldown makes GetOrLoad stop calling the loader for a key for
+	// this many milliseconds after it failed, doubling with every further
+	// consecutive failure up to MaxLoaderCooldown, which defaults to 64
+	// times LoaderCooldown. Zero retries failed keys right away.
+	LoaderCooldown    int64
+	MaxLoaderCooldown int64
+	// CallbackTimeout stops the cache from waiting for a hook after this
+	// many milliseconds. The hook carries on in its own goroutine and is
+	// counted in Stats.SlowCallbacks; a Validate that times out rejects the
+	// value, a Revalidate keeps the cached one. With a timeout, every hook
+	// call runs in a new goroutine, and panics are passed back to the
+	// calling one; a hook that panics after the cache stopped waiting is
+	// counted in Stats.CallbackPanics instead. Zero waits for hooks to
+	// return.
+	CallbackTimeout int64
+	// RecoverCallbackPanics recovers panics in hooks, counts them in
+	// Stats.CallbackPanics and otherwise treats them like timeouts.
+	RecoverCallbackPanics bool
+	// Background runs the sweeper and stats sampler on a shared, capped set
+	// of goroutines. When nil, the cache starts its own.
+	Background *Background
+	// Logger receives structured logs: evictions, expiries and rejected
+	// values at debug level, failed callbacks at warn level. When nil
+	// nothing is logged.
+	Logger *slog.Logger
+	// ExpvarName publishes the cache's stats under expvar, see
+	// PublishExpvar. Empty publishes nothing.
+	ExpvarName string
+	// RedactKeys replaces keys with a hash in logs, errors and marshaled
+	// EntryInfo, for caches keyed by personal data such as email addresses.
+	RedactKeys bool
+	// SnapshotKeys encrypts every entry written by SaveTo, its key along
+	// with its value, with a key chosen per entry. Only ages, TTLs and the
+	// key ID are left in the clear. Nil writes snapshots in the clear.
+	SnapshotKeys SnapshotKeyResolver
 }
 
-type LRUCacher[T any] interface {
+// Hooks are the callbacks a cache runs on its values. They live apart from
+// LRUCacheConfig because they depend on the key and value types.
+type Hooks[K comparable, V any] struct {
+	// Validate rejects values before they are cached. Rejected values are
+	// dropped and counted in Stats.Rejections.
+	Validate func(key K, value V) error
+	// OnEvictBatch receives entries evicted for capacity or dropped by
+	// Clear, SwapAll and LoadFrom, in batches of up to 1000. It runs after the lock is released.
+	OnEvictBatch func(entries []Entry[K, V])
+	// OnEvict receives every value that leaves the cache, one at a time and
+	// with the reason, for instance to release resources held by values. It
+	// runs after the lock is released, and for keys pinned by Acquire after
+	// the last Release.
+	OnEvict func(key K, value V, reason EvictionReason)
+	// OnExpire receives entries removed because they expired, whether the
+	// sweeper found them, a read came across them or Set replaced them
+	// first. It runs after the lock is released.
+	OnExpire func(key K, value V)
+	// OnBeforeEvict returns false to keep key when it is about to be
+	// evicted for capacity, e.g. while an order it belongs to is in
+	// flight; the next candidate is evicted instead. After 16 vetoes in a
+	// row the cache gives up and stays over ItemLimit until a later write.
+	// Vetoes are counted in Stats.EvictionVetoes. Unlike the other hooks it
+	// runs under the lock, so it must not use the cache.
+	OnBeforeEvict func(key K, value V) bool
+	// Size weighs values for LargestEntries and SizeStats, e.g. in bytes;
+	// GobSize measures their serialized size. It runs on every write,
+	// before the lock is taken.
+	Size func(key K, value V) int64
+	// Revalidate checks a value older than Config.RevalidateAfter against
+	// the backing store, typically by comparing a version or ETag. It
+	// returns changed=false if value is still current, and otherwise the
+	// fresh value. It runs on Get, after the lock is released.
+	Revalidate func(key K, value V) (fresh V, changed bool, err error)
+}
+
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+type CacheReader[T any] interface {
 	Has(key string) bool
 	Get(key string) (T, error)
+}
+
+type CacheWriter[T any] interface {
 	Set(key string, value T) T
+	// Delete removes key and reports whether it was cached.
+	Delete(key string) bool
+}
+
+type CacheManager interface {
+	// Clear drops every entry.
+	Clear()
+}
+
+type LRUCacher[T any] interface {
+	CacheReader[T]
+	CacheWriter[T]
+	CacheManager
 }
 
 type StorageItem[T any] struct {
-	Value    T
-	DeleteAt time.Time
+	Value      T
+	DeleteAt   time.Time
+	InsertedAt time.Time
+	WrittenAt  time.Time
+	AccessedAt time.Time
+	// in LRUCache.order
+	element *list.Element
+	// used since the hand passed it, see ClockEviction
+	referenced bool
+	// see SetReadOnly
+	readOnly bool
+	// set by SetWithTTL, overrides Config.TTL
+	ttl        time.Duration
+	accesses   int
+	promotedAt time.Time
+	// see Config.RevalidateAfter
+	validatedAt  time.Time
+	revalidating bool
+	expiryTick   int64
+	provenance   *Provenance
+	// see Hooks.Size
+	size int64
 }
 
-type SafeMap[T any] struct {
-	SafeMap map[string]*StorageItem[T]
+type SafeMap[K comparable, V any] struct {
+	SafeMap map[K]*StorageItem[V]
 	mu      sync.RWMutex
 }
 
-func NewSafeMap[T any]() *SafeMap[T] {
-	return &SafeMap[T]{SafeMap: make(map[string]*StorageItem[T])}
+func NewSafeMap[K comparable, V any]() *SafeMap[K, V] {
+	return &SafeMap[K, V]{SafeMap: make(map[K]*StorageItem[V])}
 }
 
-func (item *StorageItem[T]) bumpDeleteAt(ms int64) *StorageItem[T] {
-	item.DeleteAt = time.Now().Add(time.Duration(ms) * time.Millisecond)
+func (item *StorageItem[V]) bumpDeleteAt(config LRUCacheConfig, now time.Time) *StorageItem[V] {
+	item.AccessedAt = now
+	item.DeleteAt = time.Time{}
+	ttl := time.Duration(config.TTL) * time.Millisecond
+	if item.ttl > 0 {
+		ttl = item.ttl
+	}
+	if ttl > 0 {
+		start := item.AccessedAt
+		if config.TTLMode == AbsoluteTTL {
+			start = item.WrittenAt
+		}
+		item.DeleteAt = start.Add(ttl)
+	}
+	if config.IdleTTL > 0 {
+		idleAt := item.AccessedAt.Add(time.Duration(config.IdleTTL) * time.Millisecond)
+		if item.DeleteAt.IsZero() || idleAt.Before(item.DeleteAt) {
+			item.DeleteAt = idleAt
+		}
+	}
+	if config.MaxLifetime > 0 {
+		endOfLife := item.InsertedAt.Add(time.Duration(config.MaxLifetime) * time.Millisecond)
+		if item.DeleteAt.IsZero() || endOfLife.Before(item.DeleteAt) {
+			item.DeleteAt = endOfLife
+		}
+	}
 	return item
 }
 
-type InMemoryLRUCache[T any] struct {
-	Config  LRUCacheConfig
-	Storage *SafeMap[T]
+// a zero DeleteAt means the item never expires
+func (item *StorageItem[V]) expired(now time.Time) bool {
+	return !item.DeleteAt.IsZero() && !now.Before(item.DeleteAt)
+}
+
+// now is the time entries are written, read and expired at.
+func (cache *LRUCache[K, V]) now() time.Time {
+	if cache.clock != nil {
+		return cache.clock()
+	}
+	return time.Now()
+}
+
+// expired is like item.expired, but nothing expires while expiry is paused.
+func (cache *LRUCache[K, V]) expired(item *StorageItem[V], now time.Time) bool {
+	return !cache.expiryPaused.Load() && item.expired(now)
+}
+
+// LRUCache is an in-memory LRU cache with keys of any comparable type. The
+// zero value is an empty, unlimited cache without expiry. Setting Config
+// before first use is fine; storage and the sweeper are started lazily.
+type LRUCache[K comparable, V any] struct {
+	Config   LRUCacheConfig
+	Hooks    Hooks[K, V]
+	Storage  *SafeMap[K, V]
+	initOnce sync.Once
+	frozen   atomic.Pointer[map[K]*StorageItem[V]]
+	stats    cacheStats
+	// time.Now unless a test sets it, see now
+	clock func() time.Time
+	// see PauseExpiry
+	expiryPaused atomic.Bool
+	// set once SetWithTTL is used, which turns on expiry bookkeeping
+	entryTTLs   atomic.Bool
+	sweeperOnce sync.Once
+	// closed when the sweeper exits, nil if it never started or runs on
+	// Config.Background
+	sweeperExited chan struct{}
+	stopSweeper   func(wait bool)
+	// closed by Close
+	stopOnce  sync.Once
+	stop      chan struct{}
+	closeOnce sync.Once
+	// in-flight GetOrLoad calls
+	loadMu   sync.Mutex
+	loading  map[K]*loadCall[V]
+	failures map[K]*loadFailure
+	// guarded by Storage.mu
+	// keys, most recently used first
+	order *list.List
+	// nil for LRUEviction
+	policy evictionPolicy[K]
+	wheel  expiryWheel[K]
+	// only built for string keys
+	index *keyTrie
+	// entries waiting for Hooks.OnEvict and Hooks.OnExpire
+	removals []removal[K, V]
+	// K -> publishedEntry[V], or publishedAlias[K] for aliases, for
+	// Config.WriteOnce, written under the lock and read without it
+	published atomic.Pointer[sync.Map]
+	// see Acquire, guarded by Storage.mu
+	pins map[K]int
+	// removals of pinned keys, reported by the last Release
+	held map[K][]removal[K, V]
+	// nil unless Config.VictimCacheSize is set
+	victims *victimCache[K, V]
+	// alias -> primary key and back, see SetWithAliases, guarded by
+	// Storage.mu
+	aliases   map[K]K
+	aliasesOf map[K][]K
+}
+
+// InMemoryLRUCache is the string-keyed LRUCache.
+type InMemoryLRUCache[T any] = LRUCache[string, T]
+
+// NewLRUCache returns an empty cache with the given config.
+func NewLRUCache[K comparable, V any](config LRUCacheConfig) *LRUCache[K, V] {
+	return newLRUCache(config, Hooks[K, V]{})
 }
 
-func (cache *InMemoryLRUCache[T]) Has(key string) bool {
+// newLRUCache sets the hooks before the sweeper can start and run them.
+func newLRUCache[K comparable, V any](config LRUCacheConfig, hooks Hooks[K, V]) *LRUCache[K, V] {
+	cache := &LRUCache[K, V]{Config: config, Hooks: hooks, Storage: NewSafeMap[K, V]()}
+	cache.stats.createdAt = time.Now()
+	cache.init()
+	if config.ExpvarName != "" {
+		PublishExpvar(config.ExpvarName, cache)
+	}
+	return cache
+}
+
+func (cache *LRUCache[K, V]) init() {
+	cache.initOnce.Do(func() {
+		if cache.Storage == nil {
+			cache.Storage = NewSafeMap[K, V]()
+		}
+		if cache.Storage.SafeMap == nil {
+			cache.Storage.SafeMap = make(map[K]*StorageItem[V])
+		}
+		cache.order = list.New()
+		for key, item := range cache.Storage.SafeMap {
+			item.element = cache.order.PushFront(key)
+		}
+		cache.policy = cache.newEvictionPolicy(cache.order)
+		cache.stats.background = cache.Config.Background
+		cache.wheel = cache.newExpiryWheel()
+		if cache.index = cache.newKeyIndex(); cache.index != nil {
+			for key := range cache.Storage.SafeMap {
+				cache.index.insert(any(key).(string))
+			}
+		}
+		cache.published.Store(cache.newPublished(cache.Storage.SafeMap))
+		cache.victims = cache.newVictimCache()
+		if cache.expires() {
+			cache.startSweeper()
+		}
+	})
+}
+
+func (cache *LRUCache[K, V]) expires() bool {
+	return cache.Config.TTL > 0 || cache.Config.IdleTTL > 0 || cache.Config.MaxLifetime > 0 || cache.entryTTLs.Load()
+}
+
+func (cache *LRUCache[K, V]) startSweeper() {
+	cache.sweeperOnce.Do(func() {
+		if cache.Config.Background != nil {
+			cache.stopSweeper = cache.Config.Background.every(cache.wheel.tick, cache.sweepKeys)
+			return
+		}
+		cache.sweeperExited = make(chan struct{})
+		go cache.startMessageListener(cache.wheel.tick)
+	})
+}
+
+func (cache *LRUCache[K, V]) stopped() chan struct{} {
+	cache.stopOnce.Do(func() { cache.stop = make(chan struct{}) })
+	return cache.stop
+}
+
+// Close stops the sweeper and stats goroutines and waits for them to exit.
+// Writes are applied synchronously, so there is nothing to flush. The cache
+// stays usable afterwards, but expired entries are no longer swept and rolling
+// stats windows stop updating. Close always returns nil.
+func (cache *LRUCache[K, V]) Close() error {
+	cache.closeOnce.Do(func() {
+		close(cache.stopped())
+		// keeps the sweeper from starting after Close
+		cache.sweeperOnce.Do(func() {})
+		if cache.sweeperExited != nil {
+			<-cache.sweeperExited
+		}
+		if cache.stopSweeper != nil {
+			cache.stopSweeper(true)
+		}
+		cache.stats.close()
+	})
+	return nil
+}
+
+func (cache *LRUCache[K, V]) newExpiryWheel() expiryWheel[K] {
+	if cache.Config.ExpiryTick > 0 {
+		return newExpiryWheel[K](time.Duration(cache.Config.ExpiryTick) * time.Millisecond)
+	}
+	return newExpiryWheel[K](defaultExpiryTick)
+}
+
+// newKeyIndex returns an empty prefix index if the config asks for one and
+// the keys are strings.
+func (cache *LRUCache[K, V]) newKeyIndex() *keyTrie {
+	if _, isString := any(*new(K)).(string); !isString || !cache.Config.PrefixIndex {
+		return nil
+	}
+	return newKeyTrie()
+}
+
+func (cache *LRUCache[K, V]) newStorageItem(value V, ttl time.Duration) *StorageItem[V] {
+	item := &StorageItem[V]{Value: value, ttl: ttl}
+	if cache.expires() || cache.Config.PromoteInterval > 0 || cache.Config.RevalidateAfter > 0 {
+		now := cache.now()
+		item.promotedAt = now
+		item.validatedAt = now
+		if cache.expires() {
+			item.InsertedAt = now
+			item.WrittenAt = now
+			item.bumpDeleteAt(cache.Config, now)
+		}
+	}
+	return item
+}
+
+// callers must hold the write lock
+func (cache *LRUCache[K, V]) promote(key K, item *StorageItem[V]) {
+	if every := cache.Config.PromoteEvery; every > 1 {
+		item.accesses++
+		if item.accesses < every {
+			return
+		}
+		item.accesses = 0
+	}
+	if cache.Config.PromoteInterval > 0 {
+		now := cache.now()
+		if now.Sub(item.promotedAt) < time.Duration(cache.Config.PromoteInterval)*time.Millisecond {
+			return
+		}
+		item.promotedAt = now
+	}
+	cache.moveToFront(key, item)
+}
+
+// callers must hold the write lock
+func (cache *LRUCache[K, V]) touch(key K, item *StorageItem[V]) {
+	cache.promote(key, item)
+	if cache.expires() {
+		item.bumpDeleteAt(cache.Config, cache.now())
+		item.expiryTick = cache.wheel.schedule(key, item.expiryTick, item.DeleteAt)
+	}
+}
+
+func (cache *LRUCache[K, V]) Has(key K) bool {
+	if safeMap := cache.frozen.Load(); safeMap != nil {
+		_, exists := (*safeMap)[cache.resolve(key)]
+		return exists
+	}
+	cache.init()
+	if _, published := cache.lookupPublished(key); published {
+		return true
+	}
+	defer cache.notifyRemoved()
 	cache.Storage.mu.Lock()
 	defer cache.Storage.mu.Unlock()
+	key = cache.resolve(key)
+	now := cache.now()
 	storageItem, exists := cache.Storage.SafeMap[key]
 	if !exists {
+		storageItem, exists = cache.readmit(key, now)
+	}
+	if !exists {
+		return false
+	}
+	if cache.expired(storageItem, now) {
+		cache.expireKey(key, now)
 		return false
 	}
-	storageItem.bumpDeleteAt(cache.Config.TTL)
+	cache.touch(key, storageItem)
 	return exists
 }
 
-func (cache *InMemoryLRUCache[T]) Get(key string) (T, error) {
+func (cache *LRUCache[K, V]) Get(key K) (V, error) {
+	if safeMap := cache.frozen.Load(); safeMap != nil {
+		storageItem, exists := (*safeMap)[cache.resolve(key)]
+		if !exists {
+			cache.stats.misses.Add(1)
+			var zero V
+			return zero, ErrKeyNotFound
+		}
+		cache.stats.hits.Add(1)
+		return storageItem.Value, nil
+	}
+	cache.init()
+	if value, published := cache.lookupPublished(key); published {
+		cache.stats.hits.Add(1)
+		return value, nil
+	}
+	cache.Storage.mu.Lock()
+	key = cache.resolve(key)
+	now := cache.now()
+	storageItem, exists := cache.Storage.SafeMap[key]
+	err := ErrKeyNotFound
+	if exists && cache.expired(storageItem, now) {
+		// the sweeper may not have got to it yet
+		cache.expireKey(key, now)
+		exists = false
+		err = ErrKeyExpired
+	}
+	readmitted := false
+	if !exists {
+		storageItem, exists = cache.readmit(key, now)
+		readmitted = exists
+	}
+	if !exists {
+		cache.recordMiss(key)
+		cache.Storage.mu.Unlock()
+		cache.notifyRemoved()
+		cache.stats.misses.Add(1)
+		var zero V
+		return zero, err
+	}
+	cache.stats.hits.Add(1)
+	cache.touch(key, storageItem)
+	value := storageItem.Value
+	revalidate := cache.startRevalidation(storageItem, now)
+	if !revalidate && storageItem.revalidating {
+		cache.stats.staleHits.Add(1)
+	}
+	cache.Storage.mu.Unlock()
+	if readmitted {
+		cache.notifyRemoved()
+	}
+	if revalidate {
+		return cache.revalidate(key, storageItem), nil
+	}
+	return value, nil
+}
+
+// GetOK is Get for callers that treat a miss as normal control flow. It
+// counts hits and misses and promotes entries just like Get.
+func (cache *LRUCache[K, V]) GetOK(key K) (V, bool) {
+	value, err := cache.Get(key)
+	return value, err == nil
+}
+
+// Peek returns the value of a live entry without extending its TTL or
+// making it more recently used.
+func (cache *LRUCache[K, V]) Peek(key K) (V, bool) {
+	var zero V
+	if safeMap := cache.frozen.Load(); safeMap != nil {
+		storageItem, exists := (*safeMap)[cache.resolve(key)]
+		if !exists {
+			return zero, false
+		}
+		return storageItem.Value, true
+	}
+	cache.init()
+	cache.Storage.mu.RLock()
+	defer cache.Storage.mu.RUnlock()
+	storageItem, exists := cache.Storage.SafeMap[cache.resolve(key)]
+	if !exists || cache.expired(storageItem, cache.now()) {
+		return zero, false
+	}
+	return storageItem.Value, true
+}
+
+func (cache *LRUCache[K, V]) Set(key K, value V) V {
+	return cache.set(key, value, nil, 0)
+}
+
+// SetWithTTL is like Set, but the entry expires after ttl instead of
+// Config.TTL, following Config.TTLMode. IdleTTL and MaxLifetime still apply.
+// A ttl of zero or less uses Config.TTL.
+func (cache *LRUCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) V {
+	if ttl > 0 {
+		cache.init()
+		cache.entryTTLs.Store(true)
+		cache.startSweeper()
+	}
+	return cache.set(key, value, nil, ttl)
+}
+
+func (cache *LRUCache[K, V]) set(key K, value V, provenance *Provenance, ttl time.Duration) V {
+	if cache.Config.ValidateOnSet && cache.validate(key, value) != nil {
+		return value
+	}
+	return cache.setValid(key, value, provenance, ttl)
+}
+
+// setValid is set for values that have been validated already.
+func (cache *LRUCache[K, V]) setValid(key K, value V, provenance *Provenance, ttl time.Duration) V {
+	cache.init()
+	size := cache.weigh(key, value)
+	var evicted []Entry[K, V]
+	defer func() {
+		cache.notifyRemoved()
+		cache.notifyEvicted(evicted, CapacityEvicted)
+	}()
 	cache.Storage.mu.Lock()
 	defer cache.Storage.mu.Unlock()
+	key = cache.resolve(key)
+	if cache.rejectFrozenWrite() || cache.rejectReadOnlyWrite(key) {
+		return value
+	}
+	evicted = cache.store(key, value, provenance, ttl, size)
+	return value
+}
+
+// GetOrSet returns the live value of key if there is one, and otherwise
+// sets it to value. Both happen under one lock, so concurrent GetOrSet calls
+// agree on a single value.
+func (cache *LRUCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool) {
+	if safeMap := cache.frozen.Load(); safeMap != nil {
+		if storageItem, exists := (*safeMap)[cache.resolve(key)]; exists {
+			cache.stats.hits.Add(1)
+			return storageItem.Value, true
+		}
+		// a miss is a write, and must not readmit from the victim cache
+		// either: frozen reads don't take the lock
+		cache.rejectFrozenWrite()
+		return value, false
+	}
+	valid := !cache.Config.ValidateOnSet || cache.validate(key, value) == nil
+	size := cache.weigh(key, value)
+	cache.init()
+	var evicted []Entry[K, V]
+	defer func() {
+		cache.notifyRemoved()
+		cache.notifyEvicted(evicted, CapacityEvicted)
+	}()
+	cache.Storage.mu.Lock()
+	defer cache.Storage.mu.Unlock()
+	key = cache.resolve(key)
+	now := cache.now()
 	storageItem, exists := cache.Storage.SafeMap[key]
-	var zero T
 	if !exists {
-		return zero, errors.New("key not found on LRU cache")
+		storageItem, exists = cache.readmit(key, now)
+	}
+	if exists && !cache.expired(storageItem, now) {
+		cache.stats.hits.Add(1)
+		cache.touch(key, storageItem)
+		return storageItem.Value, true
 	}
-	storageItem.bumpDeleteAt(cache.Config.TTL)
-	return storageItem.Value, nil
+	if !valid || cache.rejectFrozenWrite() {
+		return value, false
+	}
+	evicted = cache.store(key, value, nil, 0, size)
+	return value, false
 }
 
-func (cache *InMemoryLRUCache[T]) Set(key string, value T) T {
+// store makes room for key and stores value, returning the entries evicted
+// for it. Callers must hold the write lock.
+func (cache *LRUCache[K, V]) store(key K, value V, provenance *Provenance, ttl time.Duration, size int64) []Entry[K, V] {
+	var evicted []Entry[K, V]
+	cache.forgetVictim(key, Replaced)
+	if cache.full() && cache.Config.InlineExpiryBudget > 0 && !cache.expiryPaused.Load() {
+		now := cache.now()
+		for _, key := range cache.wheel.take(now, cache.Config.InlineExpiryBudget) {
+			cache.expireKey(key, now)
+		}
+	}
+	if _, exists := cache.Storage.SafeMap[key]; !exists && cache.full() {
+		evicted = cache.removeOldestKey()
+	}
+
+	storageItem := cache.newStorageItem(value, ttl)
+	storageItem.provenance = provenance
+	storageItem.size = size
+	if previous, exists := cache.Storage.SafeMap[key]; exists {
+		if cache.expired(previous, storageItem.WrittenAt) {
+			cache.recordRemoval(key, previous.Value, Expired)
+		} else {
+			cache.recordRemoval(key, previous.Value, Replaced)
+		}
+		if cache.Config.MaxLifetime > 0 && !previous.expired(storageItem.WrittenAt) {
+			storageItem.InsertedAt = previous.InsertedAt
+			storageItem.bumpDeleteAt(cache.Config, storageItem.WrittenAt)
+		}
+		cache.wheel.remove(key, previous.expiryTick)
+		storageItem.element = previous.element
+		cache.moveToFront(key, storageItem)
+	} else {
+		storageItem.element = cache.order.PushFront(key)
+		if cache.policy != nil {
+			cache.policy.added(key)
+		}
+		if cache.index != nil {
+			cache.index.insert(any(key).(string))
+		}
+	}
+	storageItem.expiryTick = cache.wheel.schedule(key, 0, storageItem.DeleteAt)
+	cache.Storage.SafeMap[key] = storageItem
+	cache.publish(key, storageItem)
+	if provenance != nil {
+		cache.stats.fills.Add(1)
+	} else {
+		cache.stats.sets.Add(1)
+	}
+	return evicted
+}
+
+func (cache *LRUCache[K, V]) Delete(key K) bool {
+	cache.init()
+	defer cache.notifyRemoved()
 	cache.Storage.mu.Lock()
 	defer cache.Storage.mu.Unlock()
-	if int64(len(cache.Storage.SafeMap)) >= cache.Config.ItemLimit {
-		cache.removeOldestKey()
+	if cache.rejectFrozenWrite() {
+		return false
 	}
+	return cache.deleteLive(cache.resolve(key), cache.now())
+}
 
-	cache.Storage.SafeMap[key] = &StorageItem[T]{Value: value, DeleteAt: time.Now().Add(time.Duration(cache.Config.TTL) * time.Millisecond)}
-	return value
+// deleteLive removes key and reports whether it was live; expired entries
+// are expired instead. Callers must hold the write lock.
+func (cache *LRUCache[K, V]) deleteLive(key K, now time.Time) bool {
+	cache.dropAliases(key)
+	cache.forgetVictim(key, Deleted)
+	storageItem, exists := cache.Storage.SafeMap[key]
+	if !exists {
+		return false
+	}
+	if cache.expired(storageItem, now) {
+		cache.expireKey(key, now)
+		return false
+	}
+	cache.recordRemoval(key, storageItem.Value, Deleted)
+	cache.deleteKey(key)
+	return true
+}
+
+// Len returns the number of live entries, leaving out expired ones the
+// sweeper hasn't removed yet.
+func (cache *LRUCache[K, V]) Len() int {
+	if safeMap := cache.frozen.Load(); safeMap != nil {
+		return len(*safeMap)
+	}
+	cache.init()
+	cache.Storage.mu.RLock()
+	defer cache.Storage.mu.RUnlock()
+	if !cache.expires() {
+		return len(cache.Storage.SafeMap)
+	}
+	now := cache.now()
+	count := 0
+	for _, item := range cache.Storage.SafeMap {
+		if !cache.expired(item, now) {
+			count++
+		}
+	}
+	return count
+}
+
+// Cap returns the configured ItemLimit; zero or less means unlimited.
+func (cache *LRUCache[K, V]) Cap() int64 {
+	return cache.Config.ItemLimit
+}
+
+// Clear atomically drops every entry along with the expiry and prefix
+// indexes. Stats are kept. Entries that had expired but weren't swept yet
+// are reported as expired rather than cleared.
+func (cache *LRUCache[K, V]) Clear() {
+	cache.init()
+	defer cache.notifyRemoved()
+	var evicted []Entry[K, V]
+	cache.swap(make(map[K]*StorageItem[V]), list.New(), func(previous map[K]*StorageItem[V]) {
+		evicted = cache.retireAll(previous, nil)
+	})
+	cache.notifyEvicted(evicted, Cleared)
+}
+
+// retireAll reports the entries of previous, the storage replaced by swap,
+// as removed: expired ones as Expired, those whose key is in current as
+// Replaced, and the rest as Cleared. It returns the cleared entries for
+// notifyEvicted. Callers must hold the write lock.
+func (cache *LRUCache[K, V]) retireAll(previous, current map[K]*StorageItem[V]) []Entry[K, V] {
+	notify := cache.Hooks.OnEvictBatch != nil || cache.Hooks.OnEvict != nil
+	var evicted []Entry[K, V]
+	now := cache.now()
+	cleared := 0
+	for key, item := range previous {
+		if cache.expired(item, now) {
+			cache.logExpiry(key, now.Sub(item.DeleteAt))
+			cache.stats.expirations.Add(1)
+			cache.recordRemoval(key, item.Value, Expired)
+			continue
+		}
+		if _, replaced := current[key]; replaced {
+			cache.recordRemoval(key, item.Value, Replaced)
+			continue
+		}
+		cleared++
+		if !notify {
+			continue
+		}
+		entry := Entry[K, V]{Key: key, Value: item.Value}
+		if cache.pins[key] > 0 {
+			cache.hold(key, removal[K, V]{entry, Cleared})
+			continue
+		}
+		evicted = append(evicted, entry)
+	}
+	cache.stats.cleared.Add(uint64(cleared))
+	return evicted
+}
+
+// SwapAll atomically replaces the whole contents of the cache with entries.
+// The new store is built before taking the lock, so readers only ever see
+// the complete old or the complete new set. All entries are kept, even if
+// there are more than ItemLimit. The previous values are reported to the
+// hooks like those overwritten by Set, or dropped by Clear if entries has
+// no value for their key.
+func (cache *LRUCache[K, V]) SwapAll(entries map[K]V) {
+	safeMap := make(map[K]*StorageItem[V], len(entries))
+	order := list.New()
+	for key, value := range entries {
+		if cache.Config.ValidateOnSet && cache.validate(key, value) != nil {
+			continue
+		}
+		item := cache.newStorageItem(value, 0)
+		item.size = cache.weigh(key, value)
+		item.element = order.PushFront(key)
+		safeMap[key] = item
+	}
+	cache.init()
+	defer cache.notifyRemoved()
+	var evicted []Entry[K, V]
+	cache.swap(safeMap, order, func(previous map[K]*StorageItem[V]) {
+		evicted = cache.retireAll(previous, safeMap)
+	})
+	cache.notifyEvicted(evicted, Cleared)
+}
+
+// swap replaces the storage with safeMap, whose items must not be shared yet,
+// and order, which holds its keys. retire is called under the lock with the
+// previous storage along with the victim cache.
+func (cache *LRUCache[K, V]) swap(safeMap map[K]*StorageItem[V], order *list.List, retire func(previous map[K]*StorageItem[V])) {
+	wheel := cache.newExpiryWheel()
+	index := cache.newKeyIndex()
+	policy := cache.newEvictionPolicy(order)
+	published := cache.newPublished(safeMap)
+	for key, item := range safeMap {
+		item.expiryTick = wheel.schedule(key, 0, item.DeleteAt)
+		if index != nil {
+			index.insert(any(key).(string))
+		}
+	}
+	cache.Storage.mu.Lock()
+	defer cache.Storage.mu.Unlock()
+	if cache.rejectFrozenWrite() {
+		return
+	}
+	previous := cache.Storage.SafeMap
+	if cache.victims != nil {
+		for _, victim := range cache.victims.entries() {
+			previous[victim.key] = victim.item
+		}
+		cache.victims = cache.newVictimCache()
+	}
+	cache.aliases, cache.aliasesOf = nil, nil
+	wheel.cursor = cache.wheel.cursor
+	cache.Storage.SafeMap = safeMap
+	cache.order = order
+	cache.policy = policy
+	cache.wheel = wheel
+	cache.index = index
+	cache.published.Store(published)
+	retire(previous)
+}
+
+// validate runs Hooks.Validate, if any. Must be called without holding the
+// lock, as it runs user code.
+func (cache *LRUCache[K, V]) validate(key K, value V) error {
+	if cache.Hooks.Validate == nil {
+		return nil
+	}
+	var hookErr error
+	err := cache.guard("Validate", func() { hookErr = cache.Hooks.Validate(key, value) })
+	if err == nil {
+		err = hookErr
+	}
+	if err != nil {
+		cache.stats.rejections.Add(1)
+		cache.logRejection(key, err)
+	}
+	return err
+}
+
+// PauseExpiry stops entries from expiring, e.g. to keep serving stale data
+// while the backend is down. Capacity eviction carries on as usual.
+func (cache *LRUCache[K, V]) PauseExpiry() {
+	cache.expiryPaused.Store(true)
+}
+
+// ResumeExpiry undoes PauseExpiry. Entries whose deadline passed in the
+// meantime expire right away.
+func (cache *LRUCache[K, V]) ResumeExpiry() {
+	cache.expiryPaused.Store(false)
+}
+
+// Freeze makes the cache read-only: entries no longer expire or get
+// evicted, writes are handled according to Config.FrozenWrites, and reads
+// skip locking entirely.
+func (cache *LRUCache[K, V]) Freeze() {
+	cache.init()
+	cache.Storage.mu.Lock()
+	defer cache.Storage.mu.Unlock()
+	safeMap := cache.Storage.SafeMap
+	cache.frozen.Store(&safeMap)
+}
+
+// callers must hold the write lock
+func (cache *LRUCache[K, V]) rejectFrozenWrite() bool {
+	if cache.frozen.Load() == nil {
+		return false
+	}
+	if cache.Config.FrozenWrites == PanicOnFrozenWrites {
+		panic(ErrCacheFrozen)
+	}
+	return true
+}
+
+func (cache *LRUCache[K, V]) sweepKeys() {
+	now := cache.now()
+	cache.Storage.mu.Lock()
+	var keys []K
+	if cache.frozen.Load() == nil && !cache.expiryPaused.Load() {
+		keys = cache.wheel.due(now)
+	}
+	cache.Storage.mu.Unlock()
+
+	batch := cache.Config.SweepBatch
+	if batch <= 0 {
+		batch = defaultSweepBatch
+	}
+	for len(keys) > 0 {
+		n := min(len(keys), batch)
+		cache.expireKeys(keys[:n], now)
+		keys = keys[n:]
+	}
+	cache.notifyRemoved()
+}
+
+// expireKeys removes those of keys that have expired. The lock was released
+// since they were found due, so each one is checked again.
+func (cache *LRUCache[K, V]) expireKeys(keys []K, now time.Time) {
+	cache.Storage.mu.Lock()
+	defer cache.Storage.mu.Unlock()
+	if cache.frozen.Load() != nil {
+		return
+	}
+	for _, key := range keys {
+		cache.expireKey(key, now)
+	}
+}
+
+// expireKey removes key if it has expired and otherwise makes sure it stays
+// scheduled. Callers must hold the write lock.
+func (cache *LRUCache[K, V]) expireKey(key K, now time.Time) {
+	item, exists := cache.Storage.SafeMap[key]
+	if !exists {
+		return
+	}
+	if !cache.expired(item, now) {
+		item.expiryTick = cache.wheel.schedule(key, item.expiryTick, item.DeleteAt)
+		return
+	}
+	cache.logExpiry(key, now.Sub(item.DeleteAt))
+	cache.deleteKey(key)
+	cache.dropAliases(key)
+	cache.stats.expirations.Add(1)
+	cache.recordRemoval(key, item.Value, Expired)
+}
+
+// removal is an entry removed while holding the lock, to be reported once
+// it is released.
+type removal[K comparable, V any] struct {
+	Entry[K, V]
+	reason EvictionReason
 }
 
-func (cache *InMemoryLRUCache[T]) sweepKeys() {
-	now := time.Now()
-	for key, value := range cache.Storage.SafeMap {
-		diff := now.Sub(value.DeleteAt).Milliseconds()
-		if diff >= 0 {
-			fmt.Printf("deleted key automatically %s with diff %d \n", key, diff)
-			delete(cache.Storage.SafeMap, key)
+// recordRemoval counts a removed value and queues the hooks for it. Callers
+// must hold the write lock and call notifyRemoved after releasing it.
+// Evictions for capacity and Clear go through notifyEvicted instead.
+func (cache *LRUCache[K, V]) recordRemoval(key K, value V, reason EvictionReason) {
+	switch reason {
+	case Deleted:
+		cache.stats.deletions.Add(1)
+	case Replaced:
+		cache.stats.replacements.Add(1)
+	}
+	if cache.Hooks.OnEvict == nil && (reason != Expired || cache.Hooks.OnExpire == nil) {
+		return
+	}
+	removed := removal[K, V]{Entry[K, V]{Key: key, Value: value}, reason}
+	if cache.pins[key] > 0 {
+		cache.hold(key, removed)
+		return
+	}
+	cache.removals = append(cache.removals, removed)
+}
+
+// notifyRemoved runs the hooks queued by recordRemoval. It must be called
+// without holding the lock.
+func (cache *LRUCache[K, V]) notifyRemoved() {
+	if cache.Hooks.OnEvict == nil && cache.Hooks.OnExpire == nil {
+		return
+	}
+	cache.Storage.mu.Lock()
+	removals := cache.removals
+	cache.removals = nil
+	cache.Storage.mu.Unlock()
+	for _, removed := range removals {
+		if removed.reason == Expired && cache.Hooks.OnExpire != nil {
+			cache.guard("OnExpire", func() { cache.Hooks.OnExpire(removed.Key, removed.Value) })
+		}
+		if cache.Hooks.OnEvict != nil {
+			cache.guard("OnEvict", func() { cache.Hooks.OnEvict(removed.Key, removed.Value, removed.reason) })
 		}
 	}
 }
 
-func (cache *InMemoryLRUCache[T]) removeOldestKey() {
-	var oldestKey string
-	var oldestTime time.Time
+// deleteKey removes key from the storage and its indexes. Callers must hold
+// the write lock.
+func (cache *LRUCache[K, V]) deleteKey(key K) {
+	item, exists := cache.Storage.SafeMap[key]
+	if !exists {
+		return
+	}
+	cache.wheel.remove(key, item.expiryTick)
+	cache.order.Remove(item.element)
+	if cache.policy != nil {
+		cache.policy.removed(key)
+	}
+	if cache.index != nil {
+		cache.index.remove(any(key).(string))
+	}
+	cache.unpublish(key)
+	delete(cache.Storage.SafeMap, key)
+}
 
-	for key, value := range cache.Storage.SafeMap {
-		if oldestKey == "" || value.DeleteAt.Before(oldestTime) {
-			oldestKey = key
-			oldestTime = value.DeleteAt
+func (cache *LRUCache[K, V]) full() bool {
+	return cache.Config.ItemLimit > 0 && int64(len(cache.Storage.SafeMap)) >= cache.Config.ItemLimit
+}
+
+func (cache *LRUCache[K, V]) removeOldestKey() []Entry[K, V] {
+	oldestKey, exists := cache.nextVictim()
+	if !exists {
+		return nil
+	}
+	item := cache.Storage.SafeMap[oldestKey]
+	cache.logEviction(oldestKey)
+	cache.deleteKey(oldestKey)
+	cache.stats.evictions.Add(1)
+	return cache.retire(oldestKey, item)
+}
+
+// maxEvictionVetoes bounds the candidates Hooks.OnBeforeEvict can turn down
+// for a single eviction.
+const maxEvictionVetoes = 16
+
+// nextVictim skips keys pinned by Acquire or vetoed by Hooks.OnBeforeEvict.
+// Callers must hold the write lock.
+func (cache *LRUCache[K, V]) nextVictim() (K, bool) {
+	if cache.policy != nil {
+		return cache.policyVictim()
+	}
+	var zero K
+	vetoes := 0
+	for element := cache.order.Back(); element != nil; {
+		key := element.Value.(K)
+		if cache.pins[key] > 0 {
+			element = element.Prev()
+			continue
 		}
+		item := cache.Storage.SafeMap[key]
+		if item.referenced {
+			// second chance
+			item.referenced = false
+			element = secondChance(cache.order, element)
+			continue
+		}
+		if cache.vetoed(key, item) {
+			if vetoes++; vetoes == maxEvictionVetoes {
+				return zero, false
+			}
+			element = element.Prev()
+			continue
+		}
+		return key, true
 	}
+	return zero, false
+}
 
-	if oldestKey != "" {
-		fmt.Printf("deleted oldest key %s \n", oldestKey)
-		delete(cache.Storage.SafeMap, oldestKey)
+// policyVictim is nextVictim for Config.Eviction policies other than plain
+// LRU. Skipped keys keep their place in the policy; once the vetoes run out,
+// every key is skipped.
+func (cache *LRUCache[K, V]) policyVictim() (K, bool) {
+	vetoes := 0
+	return cache.policy.victim(func(key K) bool {
+		if cache.pins[key] > 0 || vetoes == maxEvictionVetoes {
+			return true
+		}
+		if cache.vetoed(key, cache.Storage.SafeMap[key]) {
+			vetoes++
+			return true
+		}
+		return false
+	})
+}
+
+// vetoed asks Hooks.OnBeforeEvict whether key may be evicted. A hook that
+// fails doesn't veto. Callers must hold the write lock.
+func (cache *LRUCache[K, V]) vetoed(key K, item *StorageItem[V]) bool {
+	if cache.Hooks.OnBeforeEvict == nil {
+		return false
+	}
+	var allowed bool
+	if err := cache.guard("OnBeforeEvict", func() { allowed = cache.Hooks.OnBeforeEvict(key, item.Value) }); err != nil || allowed {
+		return false
+	}
+	cache.stats.evictionVetoes.Add(1)
+	return true
+}
+
+const evictBatchSize = 1000
+
+// notifyEvicted must be called without holding the lock.
+func (cache *LRUCache[K, V]) notifyEvicted(entries []Entry[K, V], reason EvictionReason) {
+	if cache.Hooks.OnEvict != nil {
+		for _, entry := range entries {
+			cache.guard("OnEvict", func() { cache.Hooks.OnEvict(entry.Key, entry.Value, reason) })
+		}
+	}
+	if cache.Hooks.OnEvictBatch == nil {
+		return
+	}
+	for len(entries) > 0 {
+		n := min(len(entries), evictBatchSize)
+		batch := entries[:n]
+		cache.guard("OnEvictBatch", func() { cache.Hooks.OnEvictBatch(batch) })
+		entries = entries[n:]
 	}
 }
 
-func (cache *InMemoryLRUCache[T]) startMessageListener(interval time.Duration) {
+func (cache *LRUCache[K, V]) startMessageListener(interval time.Duration) {
+	defer close(cache.sweeperExited)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	stop := cache.stopped()
 	for {
-		time.Sleep(interval)
-		cache.sweepKeys()
+		select {
+		case <-ticker.C:
+			cache.sweepKeys()
+		case <-stop:
+			return
+		}
 	}
 }
 
@@ -117,11 +1258,10 @@ type LRUCacheProvider[T any] interface {
 
 // e.g other
 // type RedisCacheProvider[T any] struct{}
-type InMemoryLRUCacheProvider[T any] struct{}
+type InMemoryLRUCacheProvider[T any] struct {
+	Hooks Hooks[string, T]
+}
 
 func (cacheProvider InMemoryLRUCacheProvider[T]) NewLRUCache(config LRUCacheConfig) LRUCacher[T] {
-	safeMap := NewSafeMap[T]()
-	cache := InMemoryLRUCache[T]{Config: config, Storage: safeMap}
-	go cache.startMessageListener(50 * time.Millisecond)
-	return &cache
+	return newLRUCache(config, cacheProvider.Hooks)
 }
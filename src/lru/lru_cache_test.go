@@ -1,6 +1,15 @@
 package lru
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,8 +22,42 @@ type UserData struct {
 	Age  int
 }
 
+// fakeClock only moves when a test advances it.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = clock.now.Add(d)
+}
+
 func TestLRUCache(t *testing.T) {
 
+	t.Run("LRU cache: interfaces", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000})
+
+		var writer CacheWriter[UserData] = lruCache
+		var reader CacheReader[UserData] = lruCache
+		writer.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		value, err := reader.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value)
+	})
+
 	t.Run("LRU cache: Has", func(t *testing.T) {
 		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
 
@@ -72,7 +115,8 @@ func TestLRUCache(t *testing.T) {
 		t.Run("fetches no value and returns error for missing key, fetches value for present key", func(t *testing.T) {
 			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1500})
 			value, err := lruCache.Get("user001")
-			assert.Error(t, err, "An error should occur for missing key 'user001'")
+			assert.ErrorIs(t, err, ErrKeyNotFound, "An error should occur for missing key 'user001'")
+			assert.NotErrorIs(t, err, ErrKeyExpired)
 			assert.Empty(t, value, "Returned value should be null for missing key 'user001'")
 
 			lruCache.Set("user001", UserData{ID: 6, Name: "Frank", Age: 22})
@@ -81,6 +125,20 @@ func TestLRUCache(t *testing.T) {
 			assert.Equal(t, UserData{ID: 6, Name: "Frank", Age: 22}, value, "Value for 'user001' should be 'Frank'")
 		})
 
+		t.Run("GetOK reports hits and misses without an error", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+			value, ok := lruCache.GetOK("user001")
+			assert.False(t, ok)
+			assert.Empty(t, value)
+
+			lruCache.Set("user001", UserData{ID: 6, Name: "Frank", Age: 22})
+			value, ok = lruCache.GetOK("user001")
+			assert.True(t, ok)
+			assert.Equal(t, UserData{ID: 6, Name: "Frank", Age: 22}, value)
+			assert.Equal(t, uint64(1), lruCache.Stats().Hits)
+			assert.Equal(t, uint64(1), lruCache.Stats().Misses)
+		})
+
 		t.Run("provides no value and error for evicted keys", func(t *testing.T) {
 			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 1, TTL: 2000})
 			lruCache.Set("user002", UserData{ID: 7, Name: "Grace", Age: 29})
@@ -167,6 +225,18 @@ func TestLRUCache(t *testing.T) {
 			assert.Equal(t, UserData{ID: 1, Name: "Alice Updated", Age: 31}, value)
 		})
 
+		t.Run("overwrites a record in a full cache without evicting another", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2, TTL: 50000})
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob Updated", Age: 26})
+
+			assert.True(t, lruCache.Has("user1"))
+			value, err := lruCache.Get("user2")
+			assert.NoError(t, err)
+			assert.Equal(t, UserData{ID: 2, Name: "Bob Updated", Age: 26}, value)
+		})
+
 		t.Run("updates existing record and extends TTL", func(t *testing.T) {
 			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 250})
 			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
@@ -303,6 +373,785 @@ func TestLRUCache(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, UserData{ID: 3, Name: "Charlie", Age: 35}, value)
 		})
+
+		t.Run("evicts by recency, not by expiry deadline", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2, TTL: 1000, TTLMode: AbsoluteTTL}).(*InMemoryLRUCache[UserData])
+			lruCache.SetWithTTL("user1", UserData{ID: 1, Name: "Alice", Age: 30}, 100*time.Millisecond)
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+			lruCache.Get("user1")
+
+			lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+			assert.Equal(t, []string{"user3", "user1"}, lruCache.Keys(), "Key 'user2' was used least recently, though it expires last")
+		})
+
+		t.Run("evicts in constant time on large caches", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 100_000, Logger: slog.New(slog.DiscardHandler)}).(*InMemoryLRUCache[UserData])
+			for i := 0; i < 100_000; i++ {
+				lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+			}
+			start := time.Now()
+			for i := 0; i < 10_000; i++ {
+				lruCache.Set(fmt.Sprintf("new%d", i), UserData{ID: i})
+			}
+			assert.Less(t, time.Since(start), 2*time.Second, "10k evictions should not scan the whole cache each")
+			assert.False(t, lruCache.Has("user9999"))
+			assert.True(t, lruCache.Has("user10000"))
+		})
+	})
+
+	t.Run("LRU cache: expired reads", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+		// a 10s tick keeps the sweeper from running during the tests
+		config := LRUCacheConfig{ItemLimit: 10, TTL: 50, ExpiryTick: 10_000}
+
+		t.Run("Get misses on entries the sweeper hasn't removed yet", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			time.Sleep(100 * time.Millisecond)
+
+			value, err := lruCache.Get("user1")
+			assert.ErrorIs(t, err, ErrKeyExpired)
+			assert.ErrorIs(t, err, ErrKeyNotFound)
+			assert.Empty(t, value)
+			assert.Equal(t, 0, lruCache.Len(), "Expired entry should be removed on read")
+			assert.Equal(t, uint64(1), lruCache.Stats().Misses)
+			assert.Equal(t, uint64(1), lruCache.Stats().Expirations)
+			_, err = lruCache.Get("user1")
+			assert.Equal(t, ErrKeyNotFound, err, "Removed entries are just not found")
+		})
+
+		t.Run("Has reports entries the sweeper hasn't removed yet as absent", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			time.Sleep(100 * time.Millisecond)
+
+			assert.False(t, lruCache.Has("user1"))
+			assert.False(t, lruCache.Has("user1"), "Has should not revive an expired entry")
+		})
+
+		t.Run("GetOrLoad reloads expired entries", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			time.Sleep(100 * time.Millisecond)
+
+			value, err := lruCache.GetOrLoad("user1", func(key string) (UserData, error) {
+				return UserData{ID: 1, Name: "Alice Reloaded", Age: 30}, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "Alice Reloaded", value.Name)
+		})
+
+		t.Run("serves expired entries while expiry is paused", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.PauseExpiry()
+			time.Sleep(100 * time.Millisecond)
+
+			_, ok := lruCache.Peek("user1")
+			assert.True(t, ok)
+			lruCache.ResumeExpiry()
+			assert.False(t, lruCache.Has("user1"))
+		})
+	})
+
+	t.Run("LRU cache: Peek", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("returns the value without promoting it", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+
+			value, ok := lruCache.Peek("user1")
+			assert.True(t, ok)
+			assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value)
+			lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+			assert.False(t, lruCache.Has("user1"), "Peeked key should still be the least recently used")
+
+			_, ok = lruCache.Peek("user4")
+			assert.False(t, ok)
+		})
+
+		t.Run("does not extend the TTL", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 150}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			for i := 0; i < 4; i++ {
+				time.Sleep(50 * time.Millisecond)
+				lruCache.Peek("user1")
+			}
+			_, ok := lruCache.Peek("user1")
+			assert.False(t, ok, "Peek should not keep the entry alive")
+		})
+
+		t.Run("doesn't count as a hit or miss", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Peek("user1")
+			lruCache.Peek("user2")
+			stats := lruCache.Stats()
+			assert.Zero(t, stats.Hits)
+			assert.Zero(t, stats.Misses)
+		})
+	})
+
+	t.Run("LRU cache: GetOrSet", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("sets absent keys and keeps present ones", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+			actual, loaded := lruCache.GetOrSet("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			assert.False(t, loaded)
+			assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, actual)
+
+			actual, loaded = lruCache.GetOrSet("user1", UserData{ID: 1, Name: "Alice Updated", Age: 31})
+			assert.True(t, loaded)
+			assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, actual)
+		})
+
+		t.Run("replaces expired entries", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 50, ExpiryTick: 60000}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			time.Sleep(100 * time.Millisecond)
+
+			actual, loaded := lruCache.GetOrSet("user1", UserData{ID: 1, Name: "Alice Updated", Age: 31})
+			assert.False(t, loaded, "Expired entry should not be returned")
+			assert.Equal(t, "Alice Updated", actual.Name)
+		})
+
+		t.Run("concurrent callers agree on one value", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+			var wg sync.WaitGroup
+			var stored atomic.Int32
+			results := make([]UserData, 50)
+			for i := range results {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					actual, loaded := lruCache.GetOrSet("user1", UserData{ID: i})
+					if !loaded {
+						stored.Add(1)
+					}
+					results[i] = actual
+				}()
+			}
+			wg.Wait()
+
+			assert.Equal(t, int32(1), stored.Load())
+			for _, result := range results {
+				assert.Equal(t, results[0], result)
+			}
+		})
+
+		t.Run("doesn't write to a frozen cache", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Freeze()
+
+			_, loaded := lruCache.GetOrSet("user1", UserData{ID: 1, Name: "Alice Updated", Age: 31})
+			assert.True(t, loaded)
+			_, loaded = lruCache.GetOrSet("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+			assert.False(t, loaded)
+			assert.False(t, lruCache.Has("user2"))
+		})
+
+		t.Run("doesn't readmit victims into a frozen cache", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 1, VictimCacheSize: 4}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+			lruCache.Freeze()
+
+			actual, loaded := lruCache.GetOrSet("user1", UserData{ID: 9})
+			assert.False(t, loaded)
+			assert.Equal(t, UserData{ID: 9}, actual)
+			assert.Equal(t, []string{"user2"}, lruCache.Keys())
+			assert.Zero(t, lruCache.Stats().PrematureEvictions)
+		})
+	})
+
+	t.Run("LRU cache: Delete", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("removes the key and reports whether it was cached", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000})
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+
+			assert.True(t, lruCache.Delete("user1"))
+			assert.False(t, lruCache.Has("user1"))
+			_, err := lruCache.Get("user1")
+			assert.Error(t, err)
+			assert.False(t, lruCache.Delete("user1"), "Deleting a missing key should report false")
+		})
+
+		t.Run("frees capacity for new entries", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2, TTL: 1000})
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+			lruCache.Delete("user2")
+			lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+
+			assert.True(t, lruCache.Has("user1"), "Key 'user1' should not be evicted")
+			assert.True(t, lruCache.Has("user3"))
+		})
+
+		t.Run("deleted keys don't expire later", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 100}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Delete("user1")
+
+			time.Sleep(200 * time.Millisecond)
+			assert.Zero(t, lruCache.Stats().Expirations)
+		})
+
+		t.Run("is dropped on a frozen cache", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Freeze()
+
+			assert.False(t, lruCache.Delete("user1"))
+			assert.True(t, lruCache.Has("user1"))
+		})
+	})
+
+	t.Run("LRU cache: Len and Cap", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("reports fill level and capacity", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2}).(*InMemoryLRUCache[UserData])
+			assert.Equal(t, 0, lruCache.Len())
+			assert.Equal(t, int64(2), lruCache.Cap())
+
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+			lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+			assert.Equal(t, 2, lruCache.Len())
+		})
+
+		t.Run("doesn't count expired entries the sweeper hasn't removed", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 50, ExpiryTick: 60000}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			assert.Equal(t, 1, lruCache.Len())
+
+			time.Sleep(100 * time.Millisecond)
+			assert.Equal(t, 0, lruCache.Len())
+		})
+	})
+
+	t.Run("LRU cache: Clear", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("drops all entries", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2, TTL: 1000, PrefixIndex: true}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+			lruCache.Clear()
+
+			assert.False(t, lruCache.Has("user1"))
+			assert.False(t, lruCache.Has("user2"))
+			assert.Zero(t, lruCache.CountPrefix("user"))
+
+			lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+			lruCache.Set("user4", UserData{ID: 4, Name: "Dave", Age: 40})
+			assert.True(t, lruCache.Has("user3"), "Cleared entries should not take up capacity")
+			assert.Zero(t, lruCache.Stats().Evictions)
+		})
+
+		t.Run("reports entries that had expired as expired", func(t *testing.T) {
+			clock := newFakeClock()
+			var expired, evicted []string
+			lruCache := &InMemoryLRUCache[UserData]{Config: LRUCacheConfig{ItemLimit: 10, TTL: 100}, clock: clock.Now}
+			lruCache.Hooks.OnExpire = func(key string, value UserData) { expired = append(expired, key) }
+			lruCache.Hooks.OnEvict = func(key string, value UserData, reason EvictionReason) {
+				evicted = append(evicted, key+" "+reason.String())
+			}
+			assert.NoError(t, lruCache.Close())
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.SetWithTTL("user2", UserData{ID: 2, Name: "Bob", Age: 25}, time.Second)
+			clock.Advance(200 * time.Millisecond)
+			lruCache.Clear()
+
+			assert.Equal(t, []string{"user1"}, expired)
+			assert.ElementsMatch(t, []string{"user1 expired", "user2 cleared"}, evicted)
+			stats := lruCache.Stats()
+			assert.Equal(t, uint64(1), stats.Expirations)
+			assert.Equal(t, uint64(1), stats.Cleared)
+		})
+
+		t.Run("is dropped on a frozen cache", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Freeze()
+			lruCache.Clear()
+			assert.True(t, lruCache.Has("user1"))
+		})
+	})
+
+	t.Run("LRU cache: SwapAll", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("replaces all entries at once", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+
+			lruCache.SwapAll(map[string]UserData{
+				"user2": {ID: 2, Name: "Bob Updated", Age: 26},
+				"user3": {ID: 3, Name: "Charlie", Age: 35},
+			})
+
+			assert.False(t, lruCache.Has("user1"), "Key 'user1' should be gone after the swap")
+			value, err := lruCache.Get("user2")
+			assert.NoError(t, err)
+			assert.Equal(t, UserData{ID: 2, Name: "Bob Updated", Age: 26}, value)
+			value, err = lruCache.Get("user3")
+			assert.NoError(t, err)
+			assert.Equal(t, UserData{ID: 3, Name: "Charlie", Age: 35}, value)
+		})
+
+		t.Run("reports the values it replaces", func(t *testing.T) {
+			var evicted, batched []string
+			lruCache := InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{
+				OnEvict: func(key string, value UserData, reason EvictionReason) {
+					evicted = append(evicted, key+" "+reason.String())
+				},
+				OnEvictBatch: func(entries []Entry[string, UserData]) {
+					for _, entry := range entries {
+						batched = append(batched, entry.Key)
+					}
+				},
+			}}.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+
+			lruCache.SwapAll(map[string]UserData{"user2": {ID: 2, Name: "Bob Updated", Age: 26}})
+			assert.ElementsMatch(t, []string{"user1 cleared", "user2 replaced"}, evicted)
+			assert.Equal(t, []string{"user1"}, batched)
+			stats := lruCache.Stats()
+			assert.Equal(t, uint64(1), stats.Cleared)
+			assert.Equal(t, uint64(1), stats.Replacements)
+		})
+
+		t.Run("swapped entries expire with the configured TTL", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 200}).(*InMemoryLRUCache[UserData])
+			lruCache.SwapAll(map[string]UserData{"user1": {ID: 1, Name: "Alice", Age: 30}})
+			assert.True(t, lruCache.Has("user1"))
+
+			time.Sleep(300 * time.Millisecond)
+			assert.False(t, lruCache.Has("user1"))
+		})
+	})
+
+	t.Run("LRU cache: Freeze", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("serves existing entries and drops writes", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Freeze()
+
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice Updated", Age: 31})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+			lruCache.SwapAll(map[string]UserData{})
+
+			value, err := lruCache.Get("user1")
+			assert.NoError(t, err)
+			assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value)
+			assert.False(t, lruCache.Has("user2"), "Writes after Freeze should be dropped")
+			_, err = lruCache.Get("user2")
+			assert.Error(t, err)
+		})
+
+		t.Run("frozen entries no longer expire", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 100}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Freeze()
+
+			time.Sleep(200 * time.Millisecond)
+			assert.True(t, lruCache.Has("user1"))
+			assert.Len(t, maps.Collect(lruCache.All()), 1)
+		})
+
+		t.Run("panics on writes when configured to", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000, FrozenWrites: PanicOnFrozenWrites}).(*InMemoryLRUCache[UserData])
+			lruCache.Freeze()
+			assert.PanicsWithValue(t, ErrCacheFrozen, func() {
+				lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			})
+		})
+	})
+
+	t.Run("LRU cache: TTL modes", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("absolute TTL is not extended by reads", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 300, TTLMode: AbsoluteTTL})
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+
+			time.Sleep(200 * time.Millisecond)
+			assert.True(t, lruCache.Has("user1"), "Key 'user1' should exist before absolute expiry")
+
+			time.Sleep(200 * time.Millisecond)
+			assert.False(t, lruCache.Has("user1"), "Key 'user1' should expire despite being read")
+		})
+
+		t.Run("absolute TTL restarts on write", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 300, TTLMode: AbsoluteTTL})
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+
+			time.Sleep(200 * time.Millisecond)
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice Updated", Age: 31})
+
+			time.Sleep(200 * time.Millisecond)
+			value, err := lruCache.Get("user1")
+			assert.NoError(t, err)
+			assert.Equal(t, UserData{ID: 1, Name: "Alice Updated", Age: 31}, value)
+		})
+
+		t.Run("idle TTL expires entries that are not accessed", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 5000, IdleTTL: 200})
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+
+			time.Sleep(150 * time.Millisecond)
+			assert.True(t, lruCache.Has("user1"))
+
+			time.Sleep(150 * time.Millisecond)
+			assert.True(t, lruCache.Has("user1"), "Accessed key 'user1' should survive the idle timeout")
+			assert.False(t, lruCache.Has("user2"), "Idle key 'user2' should be expired")
+		})
+
+		t.Run("idle and absolute TTL are enforced together", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 400, IdleTTL: 250, TTLMode: AbsoluteTTL})
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+
+			for i := 0; i < 2; i++ {
+				time.Sleep(150 * time.Millisecond)
+				assert.True(t, lruCache.Has("user1"), "Key 'user1' should be kept alive by reads")
+			}
+
+			time.Sleep(200 * time.Millisecond)
+			assert.False(t, lruCache.Has("user1"), "Key 'user1' should expire once its absolute TTL passes")
+		})
+
+		t.Run("max lifetime caps sliding TTL and re-sets", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 200, MaxLifetime: 400})
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+
+			for i := 0; i < 2; i++ {
+				time.Sleep(150 * time.Millisecond)
+				assert.True(t, lruCache.Has("user1"))
+				lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30 + i})
+			}
+
+			time.Sleep(200 * time.Millisecond)
+			assert.False(t, lruCache.Has("user1"), "Key 'user1' should expire once its max lifetime passes")
+		})
+
+		t.Run("max lifetime restarts after the entry expired", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, MaxLifetime: 200})
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+
+			time.Sleep(250 * time.Millisecond)
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 31})
+			assert.True(t, lruCache.Has("user1"))
+		})
+	})
+
+	t.Run("LRU cache: SetWithTTL", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("entries outlive or expire before the default TTL", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 300}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.SetWithTTL("user2", UserData{ID: 2, Name: "Bob", Age: 25}, 100*time.Millisecond)
+			lruCache.SetWithTTL("user3", UserData{ID: 3, Name: "Carol", Age: 41}, time.Second)
+
+			time.Sleep(200 * time.Millisecond)
+			assert.False(t, lruCache.Has("user2"), "Key 'user2' should expire after its own TTL")
+
+			time.Sleep(300 * time.Millisecond)
+			assert.False(t, lruCache.Has("user1"))
+			assert.True(t, lruCache.Has("user3"), "Key 'user3' should outlive the default TTL")
+		})
+
+		t.Run("reads extend the entry's own TTL", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 5000}).(*InMemoryLRUCache[UserData])
+			lruCache.SetWithTTL("user1", UserData{ID: 1, Name: "Alice", Age: 30}, 200*time.Millisecond)
+
+			time.Sleep(150 * time.Millisecond)
+			assert.True(t, lruCache.Has("user1"))
+			time.Sleep(150 * time.Millisecond)
+			assert.True(t, lruCache.Has("user1"), "Key 'user1' should survive after being read")
+			time.Sleep(300 * time.Millisecond)
+			assert.False(t, lruCache.Has("user1"))
+		})
+
+		t.Run("expires entries in a cache without a default TTL", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.SetWithTTL("user2", UserData{ID: 2, Name: "Bob", Age: 25}, 100*time.Millisecond)
+
+			time.Sleep(250 * time.Millisecond)
+			assert.True(t, lruCache.Has("user1"))
+			assert.False(t, lruCache.Has("user2"))
+		})
+
+		t.Run("zero uses the default TTL", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 100}).(*InMemoryLRUCache[UserData])
+			lruCache.SetWithTTL("user1", UserData{ID: 1, Name: "Alice", Age: 30}, 0)
+
+			time.Sleep(250 * time.Millisecond)
+			assert.False(t, lruCache.Has("user1"))
+		})
+	})
+
+	t.Run("LRU cache: PauseExpiry", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("keeps expired entries until resumed", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 100}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.PauseExpiry()
+			assert.True(t, lruCache.Stats().ExpiryPaused)
+
+			time.Sleep(200 * time.Millisecond)
+			value, ok := lruCache.Peek("user1")
+			assert.True(t, ok, "Entry should be served stale while expiry is paused")
+			assert.Equal(t, "Alice", value.Name)
+			assert.Equal(t, 1, lruCache.Len())
+
+			lruCache.ResumeExpiry()
+			assert.False(t, lruCache.Stats().ExpiryPaused)
+			_, ok = lruCache.Peek("user1")
+			assert.False(t, ok)
+			assert.Eventually(t, func() bool {
+				return lruCache.Stats().Expirations == 1
+			}, time.Second, 10*time.Millisecond)
+		})
+
+		t.Run("still evicts for capacity", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 1, TTL: 100}).(*InMemoryLRUCache[UserData])
+			lruCache.PauseExpiry()
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+			assert.False(t, lruCache.Has("user1"))
+		})
+	})
+
+	t.Run("LRU cache: TTL disabled", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("entries never expire", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+
+			time.Sleep(100 * time.Millisecond)
+			assert.True(t, lruCache.Has("user1"))
+			assert.True(t, lruCache.Storage.SafeMap["user1"].DeleteAt.IsZero(), "No expiry should be recorded")
+		})
+
+		t.Run("still evicts the least recently used entry", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2})
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+			assert.True(t, lruCache.Has("user1"))
+			lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+
+			assert.True(t, lruCache.Has("user1"))
+			assert.False(t, lruCache.Has("user2"))
+			assert.True(t, lruCache.Has("user3"))
+		})
+
+		t.Run("IdleTTL alone still expires idle entries", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, IdleTTL: 100})
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+
+			time.Sleep(200 * time.Millisecond)
+			assert.False(t, lruCache.Has("user1"))
+		})
+	})
+
+	t.Run("LRU cache: Close", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("stops the background goroutines", func(t *testing.T) {
+			before := runtime.NumGoroutine()
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 100}).(*InMemoryLRUCache[UserData])
+			lruCache.Stats()
+			assert.Greater(t, runtime.NumGoroutine(), before)
+
+			assert.NoError(t, lruCache.Close())
+			assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+		})
+
+		t.Run("keeps the cache usable and can be called twice", func(t *testing.T) {
+			var lruCache io.Closer = cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000}).(*InMemoryLRUCache[UserData])
+			assert.NoError(t, lruCache.Close())
+			assert.NoError(t, lruCache.Close())
+
+			cache := lruCache.(*InMemoryLRUCache[UserData])
+			cache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			assert.True(t, cache.Has("user1"))
+		})
+
+		t.Run("keeps the sweeper from starting later", func(t *testing.T) {
+			var lruCache InMemoryLRUCache[UserData]
+			assert.NoError(t, lruCache.Close())
+
+			before := runtime.NumGoroutine()
+			lruCache.SetWithTTL("user1", UserData{ID: 1, Name: "Alice", Age: 30}, time.Second)
+			assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+		})
+	})
+
+	t.Run("LRU cache: generic keys", func(t *testing.T) {
+		t.Run("works with int keys", func(t *testing.T) {
+			lruCache := NewLRUCache[int, UserData](LRUCacheConfig{ItemLimit: 2, TTL: 1000, Logger: slog.New(slog.DiscardHandler)})
+			lruCache.Set(1, UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set(2, UserData{ID: 2, Name: "Bob", Age: 25})
+			lruCache.Get(1)
+			lruCache.Set(3, UserData{ID: 3, Name: "Carol", Age: 41})
+
+			assert.Equal(t, []int{3, 1}, lruCache.Keys())
+			assert.True(t, lruCache.Has(1))
+			assert.False(t, lruCache.Has(2), "Key 2 should be evicted as least recently used")
+		})
+
+		t.Run("works with composite keys", func(t *testing.T) {
+			type tenantKey struct {
+				Tenant string
+				ID     int
+			}
+			lruCache := NewLRUCache[tenantKey, UserData](LRUCacheConfig{ItemLimit: 10})
+			lruCache.Set(tenantKey{"acme", 1}, UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set(tenantKey{"globex", 1}, UserData{ID: 1, Name: "Bob", Age: 25})
+
+			value, err := lruCache.Get(tenantKey{"acme", 1})
+			assert.NoError(t, err)
+			assert.Equal(t, "Alice", value.Name)
+			assert.True(t, lruCache.Delete(tenantKey{"globex", 1}))
+			assert.Equal(t, 1, lruCache.Len())
+		})
+
+		t.Run("expires entries", func(t *testing.T) {
+			lruCache := NewLRUCache[int, UserData](LRUCacheConfig{ItemLimit: 10, TTL: 100})
+			lruCache.Set(1, UserData{ID: 1, Name: "Alice", Age: 30})
+
+			time.Sleep(250 * time.Millisecond)
+			assert.False(t, lruCache.Has(1))
+		})
+
+		t.Run("prefix operations match nothing", func(t *testing.T) {
+			lruCache := NewLRUCache[int, UserData](LRUCacheConfig{ItemLimit: 10, PrefixIndex: true})
+			lruCache.Set(1, UserData{ID: 1, Name: "Alice", Age: 30})
+
+			assert.Zero(t, lruCache.CountPrefix(""))
+			assert.Zero(t, lruCache.DeletePrefix(""))
+			assert.True(t, lruCache.Has(1))
+		})
+
+		t.Run("snapshots round-trip", func(t *testing.T) {
+			original := NewLRUCache[int, UserData](LRUCacheConfig{ItemLimit: 10})
+			original.Set(1, UserData{ID: 1, Name: "Alice", Age: 30})
+			original.Set(2, UserData{ID: 2, Name: "Bob", Age: 25})
+
+			var buf bytes.Buffer
+			assert.NoError(t, original.SaveTo(&buf))
+			restored := NewLRUCache[int, UserData](LRUCacheConfig{ItemLimit: 10})
+			assert.NoError(t, restored.LoadFrom(&buf))
+			assert.Equal(t, []int{2, 1}, restored.Keys())
+		})
+
+		t.Run("string-keyed caches are interchangeable with InMemoryLRUCache", func(t *testing.T) {
+			var lruCache LRUCacher[UserData] = NewLRUCache[string, UserData](LRUCacheConfig{ItemLimit: 10})
+			_, ok := lruCache.(*InMemoryLRUCache[UserData])
+			assert.True(t, ok)
+		})
+	})
+
+	t.Run("LRU cache: zero value", func(t *testing.T) {
+		t.Run("is an empty unlimited cache", func(t *testing.T) {
+			var lruCache InMemoryLRUCache[UserData]
+			assert.False(t, lruCache.Has("user1"))
+			value, err := lruCache.Get("user1")
+			assert.Error(t, err)
+			assert.Empty(t, value)
+
+			for i := 0; i < 100; i++ {
+				lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+			}
+			assert.Len(t, slices.Collect(lruCache.KeysSeq()), 100)
+			value, err = lruCache.Get("user1")
+			assert.NoError(t, err)
+			assert.Equal(t, UserData{ID: 1}, value)
+		})
+
+		t.Run("works as a struct field with config set before use", func(t *testing.T) {
+			var service struct {
+				users InMemoryLRUCache[UserData]
+			}
+			service.users.Config = LRUCacheConfig{ItemLimit: 1, TTL: 100}
+			service.users.Set("user1", UserData{ID: 1})
+			service.users.Set("user2", UserData{ID: 2})
+			assert.False(t, service.users.Has("user1"))
+
+			time.Sleep(200 * time.Millisecond)
+			assert.False(t, service.users.Has("user2"), "Sweeper should be started lazily")
+		})
+
+		t.Run("supports every operation without setup", func(t *testing.T) {
+			var buf bytes.Buffer
+			var snapshotted InMemoryLRUCache[UserData]
+			assert.NoError(t, snapshotted.SaveTo(&buf))
+			var restored InMemoryLRUCache[UserData]
+			assert.NoError(t, restored.LoadFrom(&buf))
+			assert.Empty(t, maps.Collect(restored.All()))
+			assert.Zero(t, restored.Stats().Hits)
+
+			var swapped InMemoryLRUCache[UserData]
+			swapped.SwapAll(map[string]UserData{"user1": {ID: 1}})
+			assert.True(t, swapped.Has("user1"))
+
+			var frozen InMemoryLRUCache[UserData]
+			frozen.Freeze()
+			frozen.Set("user1", UserData{ID: 1})
+			assert.False(t, frozen.Has("user1"))
+
+			var deduped DedupLRUCache[UserData]
+			deduped.Set("user1", UserData{ID: 1})
+			assert.True(t, deduped.Has("user1"))
+		})
+	})
+
+	t.Run("LRU cache: concurrency", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+		t.Run("mixed operations race neither each other nor the sweeper", func(t *testing.T) {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 50, TTL: 20, ExpiryTick: 5, Logger: slog.New(slog.DiscardHandler)}).(*InMemoryLRUCache[UserData])
+			var wg sync.WaitGroup
+			for worker := 0; worker < 8; worker++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 500; i++ {
+						key := fmt.Sprintf("user%d", (worker*31+i)%100)
+						switch i % 5 {
+						case 0:
+							lruCache.Set(key, UserData{ID: i, Name: "Alice", Age: 30})
+						case 1:
+							lruCache.Get(key)
+						case 2:
+							lruCache.Has(key)
+						case 3:
+							lruCache.Peek(key)
+						case 4:
+							lruCache.Delete(key)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			assert.LessOrEqual(t, lruCache.Len(), 50)
+		})
 	})
 
 	t.Run("Arbitrary operations with UserData", func(t *testing.T) {
@@ -359,3 +1208,25 @@ func TestLRUCache(t *testing.T) {
 		})
 	})
 }
+
+func BenchmarkLRUCache(b *testing.B) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	for name, config := range map[string]LRUCacheConfig{
+		"with TTL":    {ItemLimit: 1000, TTL: 60000},
+		"without TTL": {ItemLimit: 1000},
+	} {
+		b.Run(name, func(b *testing.B) {
+			lruCache := cacheProvider.NewLRUCache(config)
+			keys := make([]string, 512)
+			for i := range keys {
+				keys[i] = fmt.Sprintf("user%d", i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				key := keys[i%len(keys)]
+				lruCache.Set(key, UserData{ID: i})
+				lruCache.Get(key)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package lruhashicorp
+
+import "lru"
+
+// LRU is the method set shared by golang-lru's lru.Cache and
+// expirable.LRU.
+type LRU[T any] interface {
+	Add(key string, value T) (evicted bool)
+	Get(key string) (value T, ok bool)
+	Remove(key string) (present bool)
+	Purge()
+}
+
+// Cache exposes a hashicorp/golang-lru cache through the lru.LRUCacher interface, for
+// migrating to or benchmarking against InMemoryLRUCache.
+type Cache[T any] struct {
+	LRU LRU[T]
+}
+
+func New[T any](cache LRU[T]) *Cache[T] {
+	return &Cache[T]{LRU: cache}
+}
+
+// Has counts as an access, like InMemoryLRUCache.Has.
+func (cache *Cache[T]) Has(key string) bool {
+	_, ok := cache.LRU.Get(key)
+	return ok
+}
+
+func (cache *Cache[T]) Get(key string) (T, error) {
+	value, ok := cache.LRU.Get(key)
+	if !ok {
+		return value, lru.ErrKeyNotFound
+	}
+	return value, nil
+}
+
+func (cache *Cache[T]) Set(key string, value T) T {
+	cache.LRU.Add(key, value)
+	return value
+}
+
+func (cache *Cache[T]) Delete(key string) bool {
+	return cache.LRU.Remove(key)
+}
+
+func (cache *Cache[T]) Clear() {
+	cache.LRU.Purge()
+}
@@ -0,0 +1,46 @@
+package lruhashicorp
+
+import (
+	"testing"
+	"time"
+
+	hashicorp "github.com/hashicorp/golang-lru/v2"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/stretchr/testify/assert"
+
+	"lru"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("serves golang-lru caches as LRUCacher", func(t *testing.T) {
+		inner, err := hashicorp.New[string, int](2)
+		assert.NoError(t, err)
+		var cache lru.LRUCacher[int] = New[int](inner)
+
+		cache.Set("a", 1)
+		cache.Set("b", 2)
+		assert.True(t, cache.Has("a"))
+		cache.Set("c", 3)
+		assert.False(t, cache.Has("b"), "Least recently used key should be evicted")
+
+		value, err := cache.Get("a")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, value)
+		_, err = cache.Get("b")
+		assert.ErrorIs(t, err, lru.ErrKeyNotFound)
+
+		assert.True(t, cache.Delete("a"))
+		assert.False(t, cache.Delete("a"))
+		cache.Clear()
+		assert.False(t, cache.Has("c"))
+	})
+
+	t.Run("serves expirable caches", func(t *testing.T) {
+		cache := New[int](expirable.NewLRU[string, int](10, nil, 50*time.Millisecond))
+		cache.Set("a", 1)
+		assert.True(t, cache.Has("a"))
+
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, cache.Has("a"))
+	})
+}
@@ -0,0 +1,182 @@
+package lruprom
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"lru"
+)
+
+// Source is implemented by InMemoryLRUCache and ShardedLRUCache.
+type Source interface {
+	Stats() lru.Stats
+	Len() int
+	Cap() int64
+}
+
+type Config struct {
+	// Namespace of every metric name, "lru" if empty.
+	Namespace string
+	// Buckets of the operation latency histogram in seconds, from 100ns to
+	// 100ms if empty.
+	Buckets []float64
+	// ConstLabels added to every metric, e.g. {"service": "api"}.
+	ConstLabels prometheus.Labels
+}
+
+// Collector is a prometheus.Collector for any number of named caches. Stats
+// are read on every scrape; latencies are only recorded for caches wrapped
+// with Instrument.
+type Collector struct {
+	mu      sync.Mutex
+	sources map[string]Source
+
+	hits      *prometheus.Desc
+	misses    *prometheus.Desc
+	hitRatio  *prometheus.Desc
+	entries   *prometheus.Desc
+	capacity  *prometheus.Desc
+	evictions *prometheus.Desc
+	premature *prometheus.Desc
+	callbacks *prometheus.Desc
+	latency   *prometheus.HistogramVec
+}
+
+func NewCollector(config Config) *Collector {
+	if config.Namespace == "" {
+		config.Namespace = "lru"
+	}
+	if len(config.Buckets) == 0 {
+		config.Buckets = prometheus.ExponentialBuckets(100e-9, 10, 7)
+	}
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(config.Namespace, "", name), help, append([]string{"cache"}, labels...), config.ConstLabels)
+	}
+	return &Collector{
+		sources:   make(map[string]Source),
+		hits:      desc("hits_total", "Reads that found a live entry."),
+		misses:    desc("misses_total", "Reads that found no live entry."),
+		hitRatio:  desc("hit_ratio", "Hits over hits and misses since the cache was created."),
+		entries:   desc("entries", "Live entries in the cache."),
+		capacity:  desc("capacity", "Configured item limit, zero or less for unlimited."),
+		evictions: desc("evictions_total", "Values removed from the cache, by reason.", "reason"),
+		premature: desc("premature_evictions_total", "Evicted entries read back from the victim cache."),
+		callbacks: desc("callback_failures_total", "Hooks that panicked or timed out, by outcome.", "outcome"),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   config.Namespace,
+			Name:        "operation_duration_seconds",
+			Help:        "Latency of cache operations.",
+			Buckets:     config.Buckets,
+			ConstLabels: config.ConstLabels,
+		}, []string{"cache", "operation"}),
+	}
+}
+
+// Register adds the cache to the metrics under name, replacing any cache
+// registered under the same name.
+func (collector *Collector) Register(name string, source Source) {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.sources[name] = source
+}
+
+// Unregister removes the cache registered under name, along with its
+// recorded latencies.
+func (collector *Collector) Unregister(name string) {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	delete(collector.sources, name)
+	collector.latency.DeletePartialMatch(prometheus.Labels{"cache": name})
+}
+
+func (collector *Collector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- collector.hits
+	descs <- collector.misses
+	descs <- collector.hitRatio
+	descs <- collector.entries
+	descs <- collector.capacity
+	descs <- collector.evictions
+	descs <- collector.premature
+	descs <- collector.callbacks
+	collector.latency.Describe(descs)
+}
+
+func (collector *Collector) Collect(metrics chan<- prometheus.Metric) {
+	collector.mu.Lock()
+	sources := make(map[string]Source, len(collector.sources))
+	for name, source := range collector.sources {
+		sources[name] = source
+	}
+	collector.mu.Unlock()
+
+	for name, source := range sources {
+		stats := source.Stats()
+		counter := func(desc *prometheus.Desc, value uint64, labels ...string) {
+			metrics <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(value), append([]string{name}, labels...)...)
+		}
+		gauge := func(desc *prometheus.Desc, value float64) {
+			metrics <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, name)
+		}
+		counter(collector.hits, stats.Hits)
+		counter(collector.misses, stats.Misses)
+		hitRatio := 0.0
+		if reads := stats.Hits + stats.Misses; reads > 0 {
+			hitRatio = float64(stats.Hits) / float64(reads)
+		}
+		gauge(collector.hitRatio, hitRatio)
+		gauge(collector.entries, float64(source.Len()))
+		gauge(collector.capacity, float64(source.Cap()))
+		counter(collector.evictions, stats.Evictions, lru.CapacityEvicted.String())
+		counter(collector.evictions, stats.Expirations, lru.Expired.String())
+		counter(collector.evictions, stats.Deletions, lru.Deleted.String())
+		counter(collector.evictions, stats.Replacements, lru.Replaced.String())
+		counter(collector.evictions, stats.Cleared, lru.Cleared.String())
+		counter(collector.premature, stats.PrematureEvictions)
+		counter(collector.callbacks, stats.CallbackPanics, "panic")
+		counter(collector.callbacks, stats.SlowCallbacks, "timeout")
+	}
+	collector.latency.Collect(metrics)
+}
+
+func (collector *Collector) observe(name, operation string, start time.Time) {
+	collector.latency.WithLabelValues(name, operation).Observe(time.Since(start).Seconds())
+}
+
+// Instrument returns cache with the latency of every operation recorded
+// under name. The cache itself still has to be registered for its stats.
+func Instrument[T any](collector *Collector, name string, cache lru.LRUCacher[T]) lru.LRUCacher[T] {
+	return &instrumented[T]{cache: cache, collector: collector, name: name}
+}
+
+type instrumented[T any] struct {
+	cache     lru.LRUCacher[T]
+	collector *Collector
+	name      string
+}
+
+func (cache *instrumented[T]) Has(key string) bool {
+	defer cache.collector.observe(cache.name, "has", time.Now())
+	return cache.cache.Has(key)
+}
+
+func (cache *instrumented[T]) Get(key string) (T, error) {
+	defer cache.collector.observe(cache.name, "get", time.Now())
+	return cache.cache.Get(key)
+}
+
+func (cache *instrumented[T]) Set(key string, value T) T {
+	defer cache.collector.observe(cache.name, "set", time.Now())
+	return cache.cache.Set(key, value)
+}
+
+func (cache *instrumented[T]) Delete(key string) bool {
+	defer cache.collector.observe(cache.name, "delete", time.Now())
+	return cache.cache.Delete(key)
+}
+
+func (cache *instrumented[T]) Clear() {
+	defer cache.collector.observe(cache.name, "clear", time.Now())
+	cache.cache.Clear()
+}
@@ -0,0 +1,112 @@
+package lruprom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"lru"
+)
+
+func TestCollector(t *testing.T) {
+	newCache := func() *lru.InMemoryLRUCache[int] {
+		lruCache := lru.InMemoryLRUCacheProvider[int]{}.NewLRUCache(lru.LRUCacheConfig{ItemLimit: 2}).(*lru.InMemoryLRUCache[int])
+		lruCache.Set("a", 1)
+		lruCache.Get("a")
+		lruCache.Get("a")
+		lruCache.Get("a")
+		lruCache.Get("b")
+		return lruCache
+	}
+
+	t.Run("reports stats of every cache", func(t *testing.T) {
+		collector := NewCollector(Config{})
+		collector.Register("sessions", newCache())
+		collector.Register("users", newCache())
+		expected := `
+# HELP lru_hit_ratio Hits over hits and misses since the cache was created.
+# TYPE lru_hit_ratio gauge
+lru_hit_ratio{cache="sessions"} 0.75
+lru_hit_ratio{cache="users"} 0.75
+# HELP lru_entries Live entries in the cache.
+# TYPE lru_entries gauge
+lru_entries{cache="sessions"} 1
+lru_entries{cache="users"} 1
+# HELP lru_capacity Configured item limit, zero or less for unlimited.
+# TYPE lru_capacity gauge
+lru_capacity{cache="sessions"} 2
+lru_capacity{cache="users"} 2
+`
+		assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "lru_hit_ratio", "lru_entries", "lru_capacity"))
+		assert.Equal(t, 2, testutil.CollectAndCount(collector, "lru_hits_total"))
+	})
+
+	t.Run("reports evictions by reason", func(t *testing.T) {
+		collector := NewCollector(Config{Namespace: "cache"})
+		lruCache := newCache()
+		lruCache.Set("a", 2)
+		lruCache.Set("b", 1)
+		lruCache.Set("c", 1)
+		lruCache.Delete("c")
+		lruCache.Clear()
+		collector.Register("sessions", lruCache)
+		expected := `
+# HELP cache_evictions_total Values removed from the cache, by reason.
+# TYPE cache_evictions_total counter
+cache_evictions_total{cache="sessions",reason="capacity"} 1
+cache_evictions_total{cache="sessions",reason="cleared"} 1
+cache_evictions_total{cache="sessions",reason="deleted"} 1
+cache_evictions_total{cache="sessions",reason="expired"} 0
+cache_evictions_total{cache="sessions",reason="replaced"} 1
+`
+		assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "cache_evictions_total"))
+	})
+
+	t.Run("reports failed callbacks", func(t *testing.T) {
+		collector := NewCollector(Config{})
+		lruCache := lru.InMemoryLRUCacheProvider[int]{Hooks: lru.Hooks[string, int]{
+			OnEvict: func(key string, value int, reason lru.EvictionReason) { panic("boom") },
+		}}.NewLRUCache(lru.LRUCacheConfig{ItemLimit: 1, RecoverCallbackPanics: true}).(*lru.InMemoryLRUCache[int])
+		lruCache.Set("a", 1)
+		lruCache.Set("b", 1)
+		collector.Register("sessions", lruCache)
+		expected := `
+# HELP lru_callback_failures_total Hooks that panicked or timed out, by outcome.
+# TYPE lru_callback_failures_total counter
+lru_callback_failures_total{cache="sessions",outcome="panic"} 1
+lru_callback_failures_total{cache="sessions",outcome="timeout"} 0
+`
+		assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "lru_callback_failures_total"))
+	})
+
+	t.Run("records latencies of instrumented caches", func(t *testing.T) {
+		collector := NewCollector(Config{})
+		lruCache := Instrument[int](collector, "sessions", newCache())
+		lruCache.Set("a", 1)
+		lruCache.Get("a")
+		lruCache.Get("b")
+		assert.Equal(t, 2, testutil.CollectAndCount(collector, "lru_operation_duration_seconds"))
+
+		collector.Unregister("sessions")
+		assert.Equal(t, 0, testutil.CollectAndCount(collector, "lru_operation_duration_seconds"))
+	})
+
+	t.Run("is a valid collector", func(t *testing.T) {
+		collector := NewCollector(Config{ConstLabels: prometheus.Labels{"service": "api"}})
+		collector.Register("sessions", newCache())
+		registry := prometheus.NewPedanticRegistry()
+		assert.NoError(t, registry.Register(collector))
+		_, err := registry.Gather()
+		assert.NoError(t, err)
+	})
+
+	t.Run("forgets unregistered caches", func(t *testing.T) {
+		collector := NewCollector(Config{})
+		collector.Register("sessions", newCache())
+		collector.Unregister("sessions")
+		assert.Equal(t, 0, testutil.CollectAndCount(collector, "lru_hits_total"))
+	})
+}
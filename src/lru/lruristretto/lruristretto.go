@@ -0,0 +1,64 @@
+package lruristretto
+
+import (
+	"time"
+
+	"github.com/dgraph-io/ristretto/v2"
+
+	"lru"
+)
+
+// Cache exposes a dgraph-io/ristretto cache through the lru.LRUCacher interface, for
+// migrating to or benchmarking against InMemoryLRUCache.
+type Cache[T any] struct {
+	Ristretto *ristretto.Cache[string, T]
+	// Cost charged per entry against the ristretto MaxCost, 1 if zero.
+	Cost int64
+	// TTL passed to ristretto for every Set, zero for no expiry.
+	TTL time.Duration
+	// Async skips waiting for ristretto's write buffers after each Set. It
+	// is much faster, but a Get right after a Set may still miss.
+	Async bool
+}
+
+func New[T any](cache *ristretto.Cache[string, T]) *Cache[T] {
+	return &Cache[T]{Ristretto: cache}
+}
+
+func (cache *Cache[T]) Has(key string) bool {
+	_, ok := cache.Ristretto.Get(key)
+	return ok
+}
+
+func (cache *Cache[T]) Get(key string) (T, error) {
+	value, ok := cache.Ristretto.Get(key)
+	if !ok {
+		return value, lru.ErrKeyNotFound
+	}
+	return value, nil
+}
+
+// Set may be dropped by ristretto's admission policy, as with ristretto
+// itself.
+func (cache *Cache[T]) Set(key string, value T) T {
+	cost := cache.Cost
+	if cost == 0 {
+		cost = 1
+	}
+	cache.Ristretto.SetWithTTL(key, value, cost, cache.TTL)
+	if !cache.Async {
+		cache.Ristretto.Wait()
+	}
+	return value
+}
+
+// Delete reports whether key was cached just before it was deleted.
+func (cache *Cache[T]) Delete(key string) bool {
+	_, ok := cache.Ristretto.Get(key)
+	cache.Ristretto.Del(key)
+	return ok
+}
+
+func (cache *Cache[T]) Clear() {
+	cache.Ristretto.Clear()
+}
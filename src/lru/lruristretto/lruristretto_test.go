@@ -0,0 +1,48 @@
+package lruristretto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/ristretto/v2"
+	"github.com/stretchr/testify/assert"
+
+	"lru"
+)
+
+func newRistretto(t *testing.T) *ristretto.Cache[string, int] {
+	inner, err := ristretto.NewCache(&ristretto.Config[string, int]{NumCounters: 1000, MaxCost: 100, BufferItems: 64})
+	assert.NoError(t, err)
+	t.Cleanup(inner.Close)
+	return inner
+}
+
+func TestCache(t *testing.T) {
+	t.Run("serves ristretto caches as LRUCacher", func(t *testing.T) {
+		var cache lru.LRUCacher[int] = New(newRistretto(t))
+
+		cache.Set("a", 1)
+		assert.True(t, cache.Has("a"), "Set should be visible right away")
+		value, err := cache.Get("a")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, value)
+		_, err = cache.Get("b")
+		assert.ErrorIs(t, err, lru.ErrKeyNotFound)
+
+		assert.True(t, cache.Delete("a"))
+		assert.False(t, cache.Delete("a"))
+		cache.Set("b", 2)
+		cache.Clear()
+		assert.False(t, cache.Has("b"))
+	})
+
+	t.Run("applies the TTL to every Set", func(t *testing.T) {
+		cache := New(newRistretto(t))
+		cache.TTL = 50 * time.Millisecond
+		cache.Set("a", 1)
+		assert.True(t, cache.Has("a"))
+
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, cache.Has("a"))
+	})
+}
@@ -0,0 +1,133 @@
+package lrustatsd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"lru"
+)
+
+// StatsSource is implemented by InMemoryLRUCache.
+type StatsSource interface {
+	StatsDelta() lru.Stats
+}
+
+type Config struct {
+	// Addr of the StatsD agent, "127.0.0.1:8125" if empty.
+	Addr string
+	// Prefix for every metric name, "lru" if empty.
+	Prefix string
+	// DogStatsD sends the cache name and Tags as DogStatsD tags. Plain
+	// StatsD has no tags, so the cache name becomes part of the metric name.
+	DogStatsD bool
+	// Tags added to every metric, e.g. "env:prod". Only sent with DogStatsD.
+	Tags []string
+	// Interval between pushes started with Start, 10s if zero.
+	Interval time.Duration
+	// Background runs the pushes started with Start instead of a goroutine
+	// of their own.
+	Background *lru.Background
+}
+
+type Exporter struct {
+	config Config
+	conn   net.Conn
+}
+
+func NewExporter(config Config) (*Exporter, error) {
+	if config.Addr == "" {
+		config.Addr = "127.0.0.1:8125"
+	}
+	if config.Prefix == "" {
+		config.Prefix = "lru"
+	}
+	if config.Interval == 0 {
+		config.Interval = 10 * time.Second
+	}
+	conn, err := net.Dial("udp", config.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to statsd at %s: %w", config.Addr, err)
+	}
+	return &Exporter{config: config, conn: conn}, nil
+}
+
+// Push sends the counter changes since the previous push and the current
+// one-minute rates for one cache. Extra tags are added to this cache's
+// metrics only, e.g. "shard:3".
+func (exporter *Exporter) Push(cacheName string, source StatsSource, tags ...string) error {
+	stats := source.StatsDelta()
+	var packet strings.Builder
+	write := func(metric string, value string, kind string) {
+		name := exporter.config.Prefix + "." + metric
+		if !exporter.config.DogStatsD {
+			name = exporter.config.Prefix + "." + cacheName + "." + metric
+		}
+		fmt.Fprintf(&packet, "%s:%s|%s", name, value, kind)
+		if exporter.config.DogStatsD {
+			allTags := append([]string{"cache:" + cacheName}, exporter.config.Tags...)
+			packet.WriteString("|#" + strings.Join(append(allTags, tags...), ","))
+		}
+		packet.WriteByte('\n')
+	}
+	counter := func(metric string, value uint64) {
+		write(metric, strconv.FormatUint(value, 10), "c")
+	}
+	gauge := func(metric string, value float64) {
+		write(metric, strconv.FormatFloat(value, 'f', -1, 64), "g")
+	}
+
+	counter("hits", stats.Hits)
+	counter("fresh_hits", stats.FreshHits)
+	counter("stale_hits", stats.StaleHits)
+	counter("error_fallbacks", stats.ErrorFallbacks)
+	counter("misses", stats.Misses)
+	counter("sets", stats.Sets)
+	counter("fills", stats.Fills)
+	counter("evictions", stats.Evictions)
+	counter("premature_evictions", stats.PrematureEvictions)
+	counter("eviction_vetoes", stats.EvictionVetoes)
+	counter("expirations", stats.Expirations)
+	counter("deletions", stats.Deletions)
+	counter("replacements", stats.Replacements)
+	counter("cleared", stats.Cleared)
+	counter("rejections", stats.Rejections)
+	counter("read_only_rejections", stats.ReadOnlyRejections)
+	counter("callback_panics", stats.CallbackPanics)
+	counter("slow_callbacks", stats.SlowCallbacks)
+	gauge("hits_per_second_1m", stats.LastMinute.HitsPerSecond)
+	gauge("miss_ratio_1m", stats.LastMinute.MissRatio)
+
+	_, err := exporter.conn.Write([]byte(strings.TrimSuffix(packet.String(), "\n")))
+	return err
+}
+
+// Start pushes the cache's stats every Interval until stop is called. Send
+// errors are dropped, as is usual for UDP metrics.
+func (exporter *Exporter) Start(cacheName string, source StatsSource, tags ...string) (stop func()) {
+	if background := exporter.config.Background; background != nil {
+		return background.Every(exporter.config.Interval, func() { exporter.Push(cacheName, source, tags...) })
+	}
+	ticker := time.NewTicker(exporter.config.Interval)
+	done := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				exporter.Push(cacheName, source, tags...)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+func (exporter *Exporter) Close() error {
+	return exporter.conn.Close()
+}
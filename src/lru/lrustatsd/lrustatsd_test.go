@@ -0,0 +1,77 @@
+package lrustatsd
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"lru"
+)
+
+func listen(t *testing.T) (net.PacketConn, func() []string) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, func() []string {
+		buf := make([]byte, 2048)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		assert.NoError(t, err)
+		return strings.Split(string(buf[:n]), "\n")
+	}
+}
+
+func TestExporter(t *testing.T) {
+	newCache := func() *lru.InMemoryLRUCache[int] {
+		lruCache := lru.InMemoryLRUCacheProvider[int]{}.NewLRUCache(lru.LRUCacheConfig{ItemLimit: 10}).(*lru.InMemoryLRUCache[int])
+		lruCache.Set("a", 1)
+		lruCache.Get("a")
+		lruCache.Get("b")
+		return lruCache
+	}
+
+	t.Run("pushes deltas as plain statsd metrics", func(t *testing.T) {
+		conn, receive := listen(t)
+		exporter, err := NewExporter(Config{Addr: conn.LocalAddr().String()})
+		assert.NoError(t, err)
+		defer exporter.Close()
+
+		lruCache := newCache()
+		assert.NoError(t, exporter.Push("sessions", lruCache))
+		lines := receive()
+		assert.Contains(t, lines, "lru.sessions.hits:1|c")
+		assert.Contains(t, lines, "lru.sessions.misses:1|c")
+		assert.Contains(t, lines, "lru.sessions.sets:1|c")
+		assert.Contains(t, lines, "lru.sessions.miss_ratio_1m:0.5|g")
+
+		lruCache.Get("a")
+		assert.NoError(t, exporter.Push("sessions", lruCache))
+		lines = receive()
+		assert.Contains(t, lines, "lru.sessions.hits:1|c")
+		assert.Contains(t, lines, "lru.sessions.sets:0|c", "Counters should only report changes")
+	})
+
+	t.Run("sends cache name and tags with dogstatsd", func(t *testing.T) {
+		conn, receive := listen(t)
+		exporter, err := NewExporter(Config{Addr: conn.LocalAddr().String(), Prefix: "app.cache", DogStatsD: true, Tags: []string{"env:test"}})
+		assert.NoError(t, err)
+		defer exporter.Close()
+
+		assert.NoError(t, exporter.Push("sessions", newCache(), "shard:3"))
+		assert.Contains(t, receive(), "app.cache.hits:1|c|#cache:sessions,env:test,shard:3")
+	})
+
+	t.Run("Start pushes periodically", func(t *testing.T) {
+		conn, receive := listen(t)
+		exporter, err := NewExporter(Config{Addr: conn.LocalAddr().String(), Interval: 20 * time.Millisecond})
+		assert.NoError(t, err)
+		defer exporter.Close()
+
+		stop := exporter.Start("sessions", newCache())
+		defer stop()
+		assert.Contains(t, receive(), "lru.sessions.hits:1|c")
+	})
+}
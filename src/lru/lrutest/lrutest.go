@@ -0,0 +1,196 @@
+package lrutest
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"lru"
+)
+
+// AssertSerializable checks that sample survives every encoding the cache
+// and its users put values through: gob, JSON, and plain and encrypted
+// snapshots. It also reports unexported fields, which every codec silently
+// drops, and nil interface fields, as gob can only decode interfaces holding
+// registered types.
+//
+// Use a sample with every field set, so dropped fields show up as
+// differences. Values are compared like reflect.DeepEqual, except that nil
+// and empty slices and maps are equal, as gob doesn't tell them apart. Strip
+// the monotonic clock reading from times with Round(0).
+func AssertSerializable[T any](t testing.TB, sample T) {
+	t.Helper()
+	checkFields(t, reflect.ValueOf(sample), fmt.Sprintf("%T", sample), map[uintptr]bool{})
+
+	var viaGob T
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&sample); err != nil {
+		t.Errorf("gob: encoding %T: %v", sample, err)
+	} else if err := gob.NewDecoder(&buf).Decode(&viaGob); err != nil {
+		t.Errorf("gob: decoding %T: %v", sample, err)
+	} else if !equal(sample, viaGob) {
+		t.Errorf("gob: %T changed in a round trip:\n sent %+v\n  got %+v", sample, sample, viaGob)
+	}
+
+	var viaJSON T
+	if data, err := json.Marshal(sample); err != nil {
+		t.Errorf("json: encoding %T: %v", sample, err)
+	} else if err := json.Unmarshal(data, &viaJSON); err != nil {
+		t.Errorf("json: decoding %T: %v", sample, err)
+	} else if !equal(sample, viaJSON) {
+		t.Errorf("json: %T changed in a round trip:\n sent %+v\n  got %+v", sample, sample, viaJSON)
+	}
+
+	checkSnapshot(t, "snapshot", lru.LRUCacheConfig{}, sample)
+	checkSnapshot(t, "encrypted snapshot", lru.LRUCacheConfig{SnapshotKeys: staticKey{}}, sample)
+}
+
+func checkSnapshot[T any](t testing.TB, name string, config lru.LRUCacheConfig, sample T) {
+	t.Helper()
+	original := lru.NewLRUCache[string, T](config)
+	defer original.Close()
+	original.Set("sample", sample)
+	var buf bytes.Buffer
+	if err := original.SaveTo(&buf); err != nil {
+		t.Errorf("%s: saving %T: %v", name, sample, err)
+		return
+	}
+
+	restored := lru.NewLRUCache[string, T](config)
+	defer restored.Close()
+	if err := restored.LoadFrom(&buf); err != nil {
+		t.Errorf("%s: loading %T: %v", name, sample, err)
+		return
+	}
+	value, err := restored.Get("sample")
+	if err != nil {
+		t.Errorf("%s: %T missing after restore", name, sample)
+	} else if !equal(sample, value) {
+		t.Errorf("%s: %T changed in a round trip:\n sent %+v\n  got %+v", name, sample, sample, value)
+	}
+}
+
+func equal[T any](a, b T) bool {
+	return equalValues(reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem())
+}
+
+func equalValues(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equalValues(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for key, elem := range a.Seq2() {
+			other := b.MapIndex(key)
+			if !other.IsValid() || !equalValues(elem, other) {
+				return false
+			}
+		}
+		return true
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equalValues(a.Elem(), b.Elem())
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return a.Elem().Type() == b.Elem().Type() && equalValues(a.Elem(), b.Elem())
+	case reflect.Struct:
+		if a.Type().Implements(gobEncoder) || reflect.PointerTo(a.Type()).Implements(gobEncoder) || !allExported(a.Type()) {
+			return reflect.DeepEqual(a.Interface(), b.Interface())
+		}
+		for i := 0; i < a.NumField(); i++ {
+			if !equalValues(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if !equalValues(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+func allExported(typ reflect.Type) bool {
+	for field := range typ.Fields() {
+		if !field.IsExported() {
+			return false
+		}
+	}
+	return true
+}
+
+// checkFields reports unexported fields anywhere in value, and interface
+// fields left nil, whose round trip therefore proves nothing.
+func checkFields(t testing.TB, value reflect.Value, path string, seen map[uintptr]bool) {
+	t.Helper()
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() || seen[value.Pointer()] {
+			return
+		}
+		seen[value.Pointer()] = true
+		checkFields(t, value.Elem(), path, seen)
+	case reflect.Interface:
+		checkFields(t, value.Elem(), path, seen)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			checkFields(t, value.Index(i), fmt.Sprintf("%s[%d]", path, i), seen)
+		}
+	case reflect.Map:
+		for key, elem := range value.Seq2() {
+			checkFields(t, elem, fmt.Sprintf("%s[%v]", path, key), seen)
+		}
+	case reflect.Struct:
+		if value.Type().Implements(gobEncoder) || reflect.PointerTo(value.Type()).Implements(gobEncoder) {
+			// encodes itself, e.g. time.Time
+			return
+		}
+		for field, fieldValue := range value.Fields() {
+			fieldPath := path + "." + field.Name
+			switch {
+			case !field.IsExported():
+				t.Errorf("%s is unexported and is dropped when the value is persisted", fieldPath)
+			case field.Type.Kind() == reflect.Interface && fieldValue.IsNil():
+				t.Errorf("%s is a nil interface; set it in the sample, as gob can only decode types registered with gob.Register", fieldPath)
+			default:
+				checkFields(t, fieldValue, fieldPath, seen)
+			}
+		}
+	}
+}
+
+var gobEncoder = reflect.TypeFor[gob.GobEncoder]()
+
+// staticKey seals every entry with the same AES key.
+type staticKey struct{}
+
+var secret = bytes.Repeat([]byte{7}, 32)
+
+func (staticKey) KeyFor(key string) (string, []byte, error) {
+	return "lrutest", secret, nil
+}
+
+func (staticKey) Key(keyID string) ([]byte, error) {
+	return secret, nil
+}
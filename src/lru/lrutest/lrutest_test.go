@@ -0,0 +1,71 @@
+package lrutest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder collects the failures AssertSerializable reports.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+type profile struct {
+	Name      string
+	Tags      []string
+	Scores    map[string]int
+	CreatedAt time.Time
+	Manager   *profile
+}
+
+type withSecret struct {
+	Name  string
+	token string
+}
+
+type withAny struct {
+	Name  string
+	Extra any
+}
+
+func TestAssertSerializable(t *testing.T) {
+	t.Run("passes for plain exported types", func(t *testing.T) {
+		r := &recorder{TB: t}
+		AssertSerializable(r, profile{
+			Name:      "Alice",
+			Tags:      []string{"admin"},
+			Scores:    map[string]int{"go": 10},
+			CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Manager:   &profile{Name: "Bob", Tags: []string{}, Scores: map[string]int{}, CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		})
+		assert.Empty(t, r.errors)
+	})
+
+	t.Run("reports unexported fields", func(t *testing.T) {
+		r := &recorder{TB: t}
+		AssertSerializable(r, withSecret{Name: "Alice", token: "s3cr3t"})
+		assert.Contains(t, r.errors, "lrutest.withSecret.token is unexported and is dropped when the value is persisted")
+		assert.NotEmpty(t, r.errors[1:], "Round trips should fail as well")
+	})
+
+	t.Run("reports nil and unregistered interface fields", func(t *testing.T) {
+		r := &recorder{TB: t}
+		AssertSerializable(r, withAny{Name: "Alice"})
+		assert.Len(t, r.errors, 1)
+		assert.Contains(t, r.errors[0], "Extra is a nil interface")
+
+		r = &recorder{TB: t}
+		AssertSerializable(r, withAny{Name: "Alice", Extra: profile{Name: "Bob"}})
+		assert.Contains(t, fmt.Sprint(r.errors), "gob: encoding")
+	})
+}
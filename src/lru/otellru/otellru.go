@@ -0,0 +1,138 @@
+package otellru
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"lru"
+)
+
+const scope = "lru/otellru"
+
+type Config struct {
+	// Name of the cache, recorded as the cache.name attribute.
+	Name string
+	// TracerProvider for the spans, otel.GetTracerProvider() if nil.
+	TracerProvider trace.TracerProvider
+	// MeterProvider for the latency histogram, otel.GetMeterProvider() if
+	// nil.
+	MeterProvider metric.MeterProvider
+}
+
+// Cache wraps an lru.LRUCacher with a span and a latency measurement for
+// every operation. Spans carry the operation, a hash of the key and, for
+// reads, whether they hit; raw keys are never recorded.
+type Cache[T any] struct {
+	cache    lru.LRUCacher[T]
+	ctx      context.Context
+	name     attribute.KeyValue
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+}
+
+func Instrument[T any](cache lru.LRUCacher[T], config Config) (*Cache[T], error) {
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+	if config.MeterProvider == nil {
+		config.MeterProvider = otel.GetMeterProvider()
+	}
+	duration, err := config.MeterProvider.Meter(scope).Float64Histogram("lru.operation.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Latency of cache operations."),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache[T]{
+		cache:    cache,
+		ctx:      context.Background(),
+		name:     attribute.String("cache.name", config.Name),
+		tracer:   config.TracerProvider.Tracer(scope),
+		duration: duration,
+	}, nil
+}
+
+// WithContext returns a copy of the cache whose spans are children of the
+// span in ctx, e.g. cache.WithContext(r.Context()).Get(key). The cache
+// itself is shared.
+func (cache *Cache[T]) WithContext(ctx context.Context) *Cache[T] {
+	copied := *cache
+	copied.ctx = ctx
+	return &copied
+}
+
+// span tracks one operation; end finishes it with the attributes only
+// known once the operation returned.
+type span[T any] struct {
+	cache      *Cache[T]
+	ctx        context.Context
+	start      time.Time
+	trace      trace.Span
+	attributes []attribute.KeyValue
+}
+
+func (cache *Cache[T]) start(operation string, key *string) *span[T] {
+	attributes := []attribute.KeyValue{cache.name, attribute.String("cache.operation", operation)}
+	options := []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindInternal), trace.WithAttributes(attributes...)}
+	if key != nil {
+		options = append(options, trace.WithAttributes(attribute.String("cache.key_hash", keyHash(*key))))
+	}
+	ctx, traceSpan := cache.tracer.Start(cache.ctx, "lru."+operation, options...)
+	return &span[T]{cache: cache, ctx: ctx, start: time.Now(), trace: traceSpan, attributes: attributes}
+}
+
+func (span *span[T]) end(attributes ...attribute.KeyValue) {
+	span.trace.SetAttributes(attributes...)
+	span.trace.End()
+	span.cache.duration.Record(span.ctx, time.Since(span.start).Seconds(), metric.WithAttributes(append(span.attributes, attributes...)...))
+}
+
+func (cache *Cache[T]) Has(key string) bool {
+	span := cache.start("has", &key)
+	cached := cache.cache.Has(key)
+	span.end(attribute.Bool("cache.hit", cached))
+	return cached
+}
+
+// Get counts any error as a miss; the wrapped cache only fails reads for
+// keys it doesn't hold.
+func (cache *Cache[T]) Get(key string) (T, error) {
+	span := cache.start("get", &key)
+	value, err := cache.cache.Get(key)
+	span.end(attribute.Bool("cache.hit", err == nil))
+	return value, err
+}
+
+func (cache *Cache[T]) Set(key string, value T) T {
+	span := cache.start("set", &key)
+	defer span.end()
+	return cache.cache.Set(key, value)
+}
+
+func (cache *Cache[T]) Delete(key string) bool {
+	span := cache.start("delete", &key)
+	deleted := cache.cache.Delete(key)
+	span.end(attribute.Bool("cache.deleted", deleted))
+	return deleted
+}
+
+func (cache *Cache[T]) Clear() {
+	span := cache.start("clear", nil)
+	defer span.end()
+	cache.cache.Clear()
+}
+
+// keyHash hashes keys the way lru's Config.RedactKeys does in logs, so the
+// spans of a key can be matched with its log lines.
+func keyHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:6])
+}
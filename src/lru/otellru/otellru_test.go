@@ -0,0 +1,107 @@
+package otellru
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"lru"
+)
+
+func TestInstrument(t *testing.T) {
+	newCache := func(t *testing.T) (*Cache[int], *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+		recorder := tracetest.NewSpanRecorder()
+		reader := sdkmetric.NewManualReader()
+		lruCache, err := Instrument[int](lru.InMemoryLRUCacheProvider[int]{}.NewLRUCache(lru.LRUCacheConfig{ItemLimit: 10}), Config{
+			Name:           "sessions",
+			TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+			MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		})
+		assert.NoError(t, err)
+		return lruCache, recorder, reader
+	}
+	attributes := func(list []attribute.KeyValue) map[attribute.Key]attribute.Value {
+		byKey := make(map[attribute.Key]attribute.Value)
+		for _, keyValue := range list {
+			byKey[keyValue.Key] = keyValue.Value
+		}
+		return byKey
+	}
+
+	t.Run("records a span per operation", func(t *testing.T) {
+		lruCache, recorder, _ := newCache(t)
+		lruCache.Set("a", 1)
+		value, err := lruCache.Get("a")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, value)
+		assert.False(t, lruCache.Has("b"))
+		assert.True(t, lruCache.Delete("a"))
+		lruCache.Clear()
+
+		spans := recorder.Ended()
+		var names []string
+		for _, span := range spans {
+			names = append(names, span.Name())
+		}
+		assert.Equal(t, []string{"lru.set", "lru.get", "lru.has", "lru.delete", "lru.clear"}, names)
+
+		get := attributes(spans[1].Attributes())
+		assert.Equal(t, "sessions", get["cache.name"].AsString())
+		assert.Equal(t, "get", get["cache.operation"].AsString())
+		assert.Equal(t, keyHash("a"), get["cache.key_hash"].AsString())
+		assert.True(t, get["cache.hit"].AsBool())
+		assert.False(t, attributes(spans[2].Attributes())["cache.hit"].AsBool())
+		assert.True(t, attributes(spans[3].Attributes())["cache.deleted"].AsBool())
+		assert.NotContains(t, attributes(spans[4].Attributes()), attribute.Key("cache.key_hash"))
+	})
+
+	t.Run("never records raw keys", func(t *testing.T) {
+		lruCache, recorder, _ := newCache(t)
+		lruCache.Set("user@example.com", 1)
+		for _, value := range attributes(recorder.Ended()[0].Attributes()) {
+			assert.NotContains(t, value.Emit(), "user@example.com")
+		}
+	})
+
+	t.Run("parents spans to the context", func(t *testing.T) {
+		lruCache, recorder, _ := newCache(t)
+		tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+		ctx, parent := tracer.Start(context.Background(), "request")
+		lruCache.WithContext(ctx).Get("a")
+		parent.End()
+		lruCache.Get("a")
+
+		spans := recorder.Ended()
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+		assert.False(t, spans[2].Parent().IsValid())
+	})
+
+	t.Run("records latencies with hits and misses", func(t *testing.T) {
+		lruCache, _, reader := newCache(t)
+		lruCache.Set("a", 1)
+		lruCache.Get("a")
+		lruCache.Get("a")
+		lruCache.Get("b")
+
+		var metrics metricdata.ResourceMetrics
+		assert.NoError(t, reader.Collect(context.Background(), &metrics))
+		histogram := metrics.ScopeMetrics[0].Metrics[0]
+		assert.Equal(t, "lru.operation.duration", histogram.Name)
+		counts := make(map[string]uint64)
+		for _, point := range histogram.Data.(metricdata.Histogram[float64]).DataPoints {
+			operation, _ := point.Attributes.Value("cache.operation")
+			name := operation.AsString()
+			if hit, ok := point.Attributes.Value("cache.hit"); ok {
+				name += " " + hit.Emit()
+			}
+			counts[name] = point.Count
+		}
+		assert.Equal(t, map[string]uint64{"set": 1, "get true": 2, "get false": 1}, counts)
+	})
+}
@@ -0,0 +1,82 @@
+package lru
+
+import "errors"
+
+// Patch updates the value of key in place. patch runs on a copy of the
+// value without holding the lock; the result is only stored if the entry
+// hasn't been replaced in the meantime, otherwise patch runs again on the
+// newer value. The copy is shallow, so patch must not modify memory shared
+// with the cached value, such as slices or maps it points to.
+//
+// Patching a frozen cache returns ErrCacheFrozen, and patching a read-only
+// entry ErrReadOnly, or they panic if configured to.
+// With ValidateOnSet, errors from Hooks.Validate are returned.
+func (cache *LRUCache[K, V]) Patch(key K, patch func(value *V)) error {
+	cache.init()
+	for {
+		current, value, err := cache.patchable(key)
+		if err != nil {
+			if errors.Is(err, ErrReadOnly) && cache.Config.FrozenWrites == PanicOnFrozenWrites {
+				panic(ErrReadOnly)
+			}
+			return err
+		}
+		patch(&value)
+		if cache.Config.ValidateOnSet {
+			if err := cache.validate(key, value); err != nil {
+				return err
+			}
+		}
+
+		if cache.republish(key, current, value, cache.weigh(key, value)) {
+			return nil
+		}
+		if cache.frozen.Load() != nil {
+			return ErrCacheFrozen
+		}
+	}
+}
+
+// patchable returns the entry of key along with a copy of its value,
+// reading both under the lock, as Get may be bumping the entry's deadline.
+func (cache *LRUCache[K, V]) patchable(key K) (*StorageItem[V], V, error) {
+	cache.Storage.mu.RLock()
+	defer cache.Storage.mu.RUnlock()
+	var zero V
+	current, exists := cache.Storage.SafeMap[key]
+	if !exists {
+		return nil, zero, ErrKeyNotFound
+	}
+	if cache.expired(current, cache.now()) {
+		return nil, zero, ErrKeyExpired
+	}
+	if current.readOnly {
+		cache.stats.readOnlyRejections.Add(1)
+		return nil, zero, ErrReadOnly
+	}
+	return current, current.Value, nil
+}
+
+// republish replaces the value of the entry if it is still current.
+func (cache *LRUCache[K, V]) republish(key K, current *StorageItem[V], value V, size int64) bool {
+	cache.Storage.mu.Lock()
+	defer cache.Storage.mu.Unlock()
+	if cache.rejectFrozenWrite() || cache.Storage.SafeMap[key] != current {
+		return false
+	}
+	item := *current
+	item.Value = value
+	item.size = size
+	// a Revalidate in flight only clears the flag on current, and gives up
+	// on the patched entry as replaced
+	item.revalidating = false
+	cache.moveToFront(key, &item)
+	if cache.expires() {
+		item.WrittenAt = cache.now()
+		item.bumpDeleteAt(cache.Config, item.WrittenAt)
+		item.expiryTick = cache.wheel.schedule(key, current.expiryTick, item.DeleteAt)
+	}
+	cache.Storage.SafeMap[key] = &item
+	cache.stats.sets.Add(1)
+	return true
+}
@@ -0,0 +1,99 @@
+package lru
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCachePatch(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+	t.Run("updates fields in place", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+
+		assert.NoError(t, lruCache.Patch("user1", func(user *UserData) { user.Age++ }))
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 31}, value)
+	})
+
+	t.Run("fails for missing keys", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		assert.ErrorIs(t, lruCache.Patch("user1", func(user *UserData) { user.Age++ }), ErrKeyNotFound)
+		assert.False(t, lruCache.Has("user1"))
+	})
+
+	t.Run("concurrent patches are not lost", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice"})
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				lruCache.Patch("user1", func(user *UserData) { user.Age++ })
+			}()
+		}
+		wg.Wait()
+
+		value, _ := lruCache.Get("user1")
+		assert.Equal(t, 50, value.Age)
+	})
+
+	t.Run("runs alongside reads", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 60_000}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice"})
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-done:
+						return
+					default:
+						lruCache.Get("user1")
+					}
+				}
+			}()
+		}
+		for i := 0; i < 5000; i++ {
+			lruCache.Patch("user1", func(user *UserData) { user.Age++ })
+		}
+		close(done)
+		wg.Wait()
+
+		value, _ := lruCache.Get("user1")
+		assert.Equal(t, 5000, value.Age)
+	})
+
+	t.Run("leaves the entry alone when validation fails", func(t *testing.T) {
+		lruCache := InMemoryLRUCacheProvider[UserData]{Hooks: Hooks[string, UserData]{Validate: func(key string, user UserData) error {
+			if user.Age < 0 {
+				return errors.New("negative age")
+			}
+			return nil
+		}}}.NewLRUCache(LRUCacheConfig{ItemLimit: 10, ValidateOnSet: true}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+
+		assert.EqualError(t, lruCache.Patch("user1", func(user *UserData) { user.Age = -1 }), "negative age")
+		value, _ := lruCache.Get("user1")
+		assert.Equal(t, 30, value.Age)
+	})
+
+	t.Run("is refused on a frozen cache", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Freeze()
+
+		assert.ErrorIs(t, lruCache.Patch("user1", func(user *UserData) { user.Age++ }), ErrCacheFrozen)
+		value, _ := lruCache.Get("user1")
+		assert.Equal(t, 30, value.Age)
+	})
+}
@@ -0,0 +1,263 @@
+package lru
+
+import "container/list"
+
+type EvictionPolicy int
+
+const (
+	// LRUEviction evicts the least recently used entry.
+	LRUEviction EvictionPolicy = iota
+	// TwoQueueEviction is 2Q: new keys go to a FIFO queue of up to a quarter
+	// of ItemLimit, and only move to the main LRU queue when they come back
+	// after being evicted from it, which is remembered for up to half of
+	// ItemLimit keys. A one-off scan thus only flushes the FIFO queue, not
+	// the hot keys in the main queue.
+	TwoQueueEviction
+	// FIFOEviction evicts the entry that was inserted first. Reads and
+	// updates don't reorder entries, so Keys lists them newest first.
+	FIFOEviction
+	// ClockEviction is CLOCK, or second chance: like FIFO, but entries read
+	// or updated since the hand last passed them are skipped once, and go to
+	// the back of the line instead of being evicted.
+	ClockEviction
+	// SegmentedLRUEviction is SLRU: new keys go to a probationary segment
+	// and move to a protected one when they are used again. Both are LRU;
+	// the protected segment is capped, demoting its least recently used key
+	// back to probation when full, and probation is evicted from first. Keys
+	// used only once thus never displace the ones used repeatedly. See
+	// LRUCacheConfig.ProbationaryRatio for the segment sizes.
+	SegmentedLRUEviction
+)
+
+func (policy EvictionPolicy) String() string {
+	switch policy {
+	case LRUEviction:
+		return "lru"
+	case TwoQueueEviction:
+		return "2q"
+	case FIFOEviction:
+		return "fifo"
+	case ClockEviction:
+		return "clock"
+	case SegmentedLRUEviction:
+		return "slru"
+	}
+	return "unknown"
+}
+
+type AdmissionPolicy int
+
+const (
+	// AdmitAll stores every key that is Set.
+	AdmitAll AdmissionPolicy = iota
+	// TinyLFUAdmission is W-TinyLFU: new keys go to a window of 1% of
+	// ItemLimit, and a key leaving the window only stays cached if it was
+	// requested more often than the key Eviction would evict for it.
+	// Request frequencies, including those of misses, are estimated with a
+	// count-min sketch that halves every 10 x ItemLimit requests, so old
+	// popularity fades.
+	TinyLFUAdmission
+)
+
+func (policy AdmissionPolicy) String() string {
+	switch policy {
+	case AdmitAll:
+		return "all"
+	case TinyLFUAdmission:
+		return "tinylfu"
+	}
+	return "unknown"
+}
+
+// evictionPolicy picks victims for policies other than plain LRU, which
+// just evicts the back of LRUCache.order. Callers must hold the write lock.
+type evictionPolicy[K comparable] interface {
+	// added is called for keys new to the cache
+	added(key K)
+	// accessed is called when a cached key is promoted or written again
+	accessed(key K)
+	// removed is called for every key leaving the cache, except victims
+	removed(key K)
+	// renamed moves a cached key to a key that isn't cached, keeping its
+	// place
+	renamed(oldKey, newKey K)
+	// victim forgets the next key to evict that skip doesn't turn down and
+	// returns it. Skipped keys keep their place.
+	victim(skip func(key K) bool) (K, bool)
+	// victims returns up to n keys in eviction order, without evicting them
+	victims(n int) []K
+}
+
+// newEvictionPolicy returns the policy for Config.Eviction, seeded with the
+// keys of order, or nil for plain LRU. Unlimited caches never evict, so they
+// don't need one either.
+func (cache *LRUCache[K, V]) newEvictionPolicy(order *list.List) evictionPolicy[K] {
+	if cache.Config.ItemLimit <= 0 {
+		return nil
+	}
+	var policy evictionPolicy[K]
+	switch cache.Config.Eviction {
+	case TwoQueueEviction:
+		policy = newTwoQueue[K](cache.Config.ItemLimit)
+	case SegmentedLRUEviction:
+		policy = newSegmentedLRU[K](cache.Config)
+	}
+	if cache.Config.Admission == TinyLFUAdmission {
+		if policy == nil {
+			policy = newLRUPolicy[K](cache.Config.Eviction)
+		}
+		policy = newTinyLFU(cache.Config.ItemLimit, policy)
+	}
+	if policy == nil {
+		return nil
+	}
+	for element := order.Back(); element != nil; element = element.Prev() {
+		policy.added(element.Value.(K))
+	}
+	return policy
+}
+
+// missRecorder is implemented by policies that learn from misses.
+type missRecorder[K comparable] interface {
+	missed(key K)
+}
+
+// recordMiss must be called with the write lock held.
+func (cache *LRUCache[K, V]) recordMiss(key K) {
+	if recorder, ok := cache.policy.(missRecorder[K]); ok {
+		recorder.missed(key)
+	}
+}
+
+// lruPolicy is LRUEviction, FIFOEviction or ClockEviction as an
+// evictionPolicy, for policies built on top of them. On their own they work
+// on LRUCache.order directly.
+type lruPolicy[K comparable] struct {
+	eviction EvictionPolicy
+	order    *list.List
+	elements map[K]*list.Element
+	// keys accessed since the hand passed them, for ClockEviction
+	referenced map[K]bool
+}
+
+func newLRUPolicy[K comparable](eviction EvictionPolicy) *lruPolicy[K] {
+	return &lruPolicy[K]{eviction: eviction, order: list.New(), elements: make(map[K]*list.Element), referenced: make(map[K]bool)}
+}
+
+func (policy *lruPolicy[K]) added(key K) {
+	policy.elements[key] = policy.order.PushFront(key)
+}
+
+func (policy *lruPolicy[K]) accessed(key K) {
+	element, exists := policy.elements[key]
+	if !exists {
+		return
+	}
+	switch policy.eviction {
+	case FIFOEviction:
+	case ClockEviction:
+		policy.referenced[key] = true
+	default:
+		policy.order.MoveToFront(element)
+	}
+}
+
+func (policy *lruPolicy[K]) removed(key K) {
+	if element, exists := policy.elements[key]; exists {
+		policy.order.Remove(element)
+		delete(policy.elements, key)
+		delete(policy.referenced, key)
+	}
+}
+
+func (policy *lruPolicy[K]) renamed(oldKey, newKey K) {
+	element, exists := policy.elements[oldKey]
+	if !exists {
+		return
+	}
+	element.Value = newKey
+	policy.elements[newKey] = element
+	delete(policy.elements, oldKey)
+	if policy.referenced[oldKey] {
+		policy.referenced[newKey] = true
+		delete(policy.referenced, oldKey)
+	}
+}
+
+func (policy *lruPolicy[K]) victim(skip func(key K) bool) (K, bool) {
+	for element := policy.order.Back(); element != nil; {
+		key := element.Value.(K)
+		if policy.referenced[key] {
+			// second chance
+			delete(policy.referenced, key)
+			element = secondChance(policy.order, element)
+			continue
+		}
+		if skip(key) {
+			element = element.Prev()
+			continue
+		}
+		policy.removed(key)
+		return key, true
+	}
+	var zero K
+	return zero, false
+}
+
+// secondChance moves element to the front of order and returns the next
+// element to look at from the back, which is element itself if it was the
+// front already.
+func secondChance(order *list.List, element *list.Element) *list.Element {
+	previous := element.Prev()
+	if previous == nil {
+		return element
+	}
+	order.MoveToFront(element)
+	return previous
+}
+
+// oldestUnskipped returns the element closest to the back of order whose
+// key skip doesn't turn down, or nil.
+func oldestUnskipped[K comparable](order *list.List, skip func(key K) bool) *list.Element {
+	for element := order.Back(); element != nil; element = element.Prev() {
+		if !skip(element.Value.(K)) {
+			return element
+		}
+	}
+	return nil
+}
+
+func (policy *lruPolicy[K]) victims(n int) []K {
+	return clockOrder(policy.order, n, func(key K) bool { return policy.referenced[key] })
+}
+
+// clockOrder returns up to n keys of order, oldest first, with the
+// referenced ones after those the hand would evict right away.
+func clockOrder[K comparable](order *list.List, n int, referenced func(key K) bool) []K {
+	keys := make([]K, 0, min(n, order.Len()))
+	var second []K
+	for element := order.Back(); element != nil && len(keys) < n; element = element.Prev() {
+		if key := element.Value.(K); referenced(key) {
+			second = append(second, key)
+		} else {
+			keys = append(keys, key)
+		}
+	}
+	keys = append(keys, second...)
+	return keys[:min(n, len(keys))]
+}
+
+// moveToFront makes key the most recently used one, or under FIFOEviction
+// and ClockEviction, marks it as used. Callers must hold the write lock.
+func (cache *LRUCache[K, V]) moveToFront(key K, item *StorageItem[V]) {
+	switch cache.Config.Eviction {
+	case FIFOEviction:
+	case ClockEviction:
+		item.referenced = true
+	default:
+		cache.order.MoveToFront(item.element)
+	}
+	if cache.policy != nil {
+		cache.policy.accessed(key)
+	}
+}
@@ -0,0 +1,86 @@
+package lru
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvictionPolicies(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	newCache := func(eviction EvictionPolicy, admission AdmissionPolicy) *InMemoryLRUCache[UserData] {
+		config := LRUCacheConfig{ItemLimit: 3, Eviction: eviction, Admission: admission, Logger: slog.New(slog.DiscardHandler)}
+		return cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+	}
+
+	t.Run("FIFO evicts in insertion order, however entries are used", func(t *testing.T) {
+		lruCache := newCache(FIFOEviction, AdmitAll)
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+		lruCache.Set("user3", UserData{ID: 3})
+		lruCache.Get("user1")
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice"})
+		assert.Equal(t, []string{"user3", "user2", "user1"}, lruCache.Keys())
+		assert.Equal(t, []string{"user1"}, lruCache.PreviewEvictions(1))
+
+		lruCache.Set("user4", UserData{ID: 4})
+		assert.False(t, lruCache.Has("user1"))
+		assert.Equal(t, []string{"user4", "user3", "user2"}, lruCache.Keys())
+	})
+
+	t.Run("CLOCK gives used entries a second chance", func(t *testing.T) {
+		lruCache := newCache(ClockEviction, AdmitAll)
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+		lruCache.Set("user3", UserData{ID: 3})
+		lruCache.Get("user1")
+		assert.Equal(t, []string{"user2", "user3", "user1"}, lruCache.PreviewEvictions(3))
+
+		lruCache.Set("user4", UserData{ID: 4})
+		assert.True(t, lruCache.Has("user1"), "Key 'user1' was used, so the hand should skip it")
+		assert.False(t, lruCache.Has("user2"))
+
+		lruCache.Set("user5", UserData{ID: 5})
+		assert.False(t, lruCache.Has("user3"))
+		assert.True(t, lruCache.Has("user4"), "Has marked 'user4' as used")
+	})
+
+	t.Run("CLOCK evicts the oldest entry once all were used", func(t *testing.T) {
+		lruCache := newCache(ClockEviction, AdmitAll)
+		for i := 1; i <= 3; i++ {
+			key := fmt.Sprintf("user%d", i)
+			lruCache.Set(key, UserData{ID: i})
+			lruCache.Get(key)
+		}
+		lruCache.Set("user4", UserData{ID: 4})
+		assert.False(t, lruCache.Has("user1"))
+		assert.Equal(t, 3, lruCache.Len())
+
+		lruCache = cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 1, Eviction: ClockEviction}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Get("user1")
+		lruCache.Set("user2", UserData{ID: 2})
+		assert.Equal(t, []string{"user2"}, lruCache.Keys())
+	})
+
+	t.Run("FIFO and CLOCK work behind TinyLFU", func(t *testing.T) {
+		for _, eviction := range []EvictionPolicy{FIFOEviction, ClockEviction} {
+			lruCache := newCache(eviction, TinyLFUAdmission)
+			for i := 0; i < 20; i++ {
+				key := fmt.Sprintf("user%d", i)
+				lruCache.Set(key, UserData{ID: i})
+				assert.True(t, lruCache.Has(key))
+			}
+			assert.Equal(t, 3, lruCache.Len(), eviction.String())
+		}
+	})
+
+	t.Run("policies have names", func(t *testing.T) {
+		assert.Equal(t, "lru", LRUEviction.String())
+		assert.Equal(t, "fifo", FIFOEviction.String())
+		assert.Equal(t, "clock", ClockEviction.String())
+		assert.Equal(t, "tinylfu", TinyLFUAdmission.String())
+	})
+}
@@ -0,0 +1,93 @@
+package lru
+
+import (
+	"iter"
+	"strings"
+)
+
+// prefixKeys returns the stored keys starting with prefix, using the prefix
+// index when enabled. Only string keys have prefixes, so for other key types
+// the prefix methods match nothing. Callers must hold the lock.
+func (cache *LRUCache[K, V]) prefixKeys(prefix string) []K {
+	var keys []K
+	if cache.index != nil {
+		for _, key := range cache.index.withPrefix(prefix) {
+			keys = append(keys, any(key).(K))
+		}
+		return keys
+	}
+	for key := range cache.Storage.SafeMap {
+		if s, ok := any(key).(string); ok && strings.HasPrefix(s, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// AllWithPrefix is like All, restricted to keys starting with prefix.
+func (cache *LRUCache[K, V]) AllWithPrefix(prefix string) iter.Seq2[K, V] {
+	cache.init()
+	cache.Storage.mu.RLock()
+	now := cache.now()
+	var entries []Entry[K, V]
+	for _, key := range cache.prefixKeys(prefix) {
+		if item := cache.Storage.SafeMap[key]; !cache.expired(item, now) {
+			entries = append(entries, Entry[K, V]{Key: key, Value: item.Value})
+		}
+	}
+	cache.Storage.mu.RUnlock()
+
+	return func(yield func(K, V) bool) {
+		for _, entry := range entries {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// CountPrefix returns the number of live entries whose key starts with prefix.
+func (cache *LRUCache[K, V]) CountPrefix(prefix string) int {
+	cache.init()
+	cache.Storage.mu.RLock()
+	defer cache.Storage.mu.RUnlock()
+	now := cache.now()
+	count := 0
+	for _, key := range cache.prefixKeys(prefix) {
+		if !cache.expired(cache.Storage.SafeMap[key], now) {
+			count++
+		}
+	}
+	return count
+}
+
+// DeletePrefix removes every entry whose key starts with prefix and returns
+// how many live entries were removed. Expired entries are expired instead
+// and left out. Like Delete, it also drops matching entries from the victim
+// cache, and leaves those out of the result too. Stats.Deletions counts
+// both the live entries and the victim cache entries.
+func (cache *LRUCache[K, V]) DeletePrefix(prefix string) int {
+	cache.init()
+	defer cache.notifyRemoved()
+	cache.Storage.mu.Lock()
+	defer cache.Storage.mu.Unlock()
+	if cache.rejectFrozenWrite() {
+		return 0
+	}
+	now := cache.now()
+	deleted := 0
+	for _, key := range cache.prefixKeys(prefix) {
+		if cache.deleteLive(key, now) {
+			deleted++
+		}
+	}
+	if cache.victims != nil {
+		for _, victim := range cache.victims.entries() {
+			if key, ok := any(victim.key).(string); ok && strings.HasPrefix(key, prefix) {
+				cache.forgetVictim(victim.key, Deleted)
+				cache.dropAliases(victim.key)
+			}
+		}
+	}
+	return deleted
+}
@@ -0,0 +1,76 @@
+package lru
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCachePrefix(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+	for _, indexed := range []bool{false, true} {
+		newCache := func() *InMemoryLRUCache[UserData] {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, PrefixIndex: indexed}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("tenant1:user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			lruCache.Set("tenant1:user2", UserData{ID: 2, Name: "Bob", Age: 25})
+			lruCache.Set("tenant2:user1", UserData{ID: 3, Name: "Charlie", Age: 35})
+			return lruCache
+		}
+		name := "without index"
+		if indexed {
+			name = "with index"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			t.Run("iterates and counts entries by prefix", func(t *testing.T) {
+				lruCache := newCache()
+				assert.Equal(t, map[string]UserData{
+					"tenant1:user1": {ID: 1, Name: "Alice", Age: 30},
+					"tenant1:user2": {ID: 2, Name: "Bob", Age: 25},
+				}, maps.Collect(lruCache.AllWithPrefix("tenant1:")))
+				assert.Equal(t, 2, lruCache.CountPrefix("tenant1:"))
+				assert.Equal(t, 0, lruCache.CountPrefix("tenant3:"))
+			})
+
+			t.Run("deletes entries by prefix", func(t *testing.T) {
+				lruCache := newCache()
+				assert.Equal(t, 2, lruCache.DeletePrefix("tenant1:"))
+				assert.False(t, lruCache.Has("tenant1:user1"))
+				assert.False(t, lruCache.Has("tenant1:user2"))
+				assert.True(t, lruCache.Has("tenant2:user1"))
+				assert.Equal(t, 0, lruCache.DeletePrefix("tenant1:"))
+			})
+
+			t.Run("counts the values it deleted", func(t *testing.T) {
+				clock := newFakeClock()
+				lruCache := &InMemoryLRUCache[UserData]{Config: LRUCacheConfig{ItemLimit: 3, VictimCacheSize: 1, PrefixIndex: indexed}, clock: clock.Now}
+				assert.NoError(t, lruCache.Close())
+				lruCache.Set("tenant1:user1", UserData{ID: 1})
+				lruCache.SetWithTTL("tenant1:user2", UserData{ID: 2}, 10*time.Millisecond)
+				lruCache.Set("tenant2:user1", UserData{ID: 3})
+				lruCache.Set("tenant1:user3", UserData{ID: 4})
+				clock.Advance(20 * time.Millisecond)
+
+				assert.Equal(t, 1, lruCache.DeletePrefix("tenant1:"), "Neither the evicted user1 nor the expired user2")
+				stats := lruCache.Stats()
+				assert.Equal(t, uint64(2), stats.Deletions)
+				assert.Equal(t, uint64(1), stats.Expirations)
+				assert.Equal(t, 1, lruCache.Len())
+			})
+
+			t.Run("keeps up with evictions and swaps", func(t *testing.T) {
+				lruCache := newCache()
+				lruCache.Config.ItemLimit = 3
+				lruCache.Set("tenant2:user2", UserData{ID: 4})
+				assert.Equal(t, 1, lruCache.CountPrefix("tenant1:"), "Evicted key should leave the index")
+
+				lruCache.SwapAll(map[string]UserData{"tenant3:user1": {ID: 5}})
+				assert.Equal(t, 0, lruCache.CountPrefix("tenant1:"))
+				assert.Equal(t, 1, lruCache.CountPrefix("tenant3:"))
+			})
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package lru
+
+// Presets return a starting configuration for common workloads. They are
+// plain values, so any field can be overridden before building the cache.
+
+// PresetSessionStore keeps sessions alive while they are in use, logs them
+// out after 30 minutes of inactivity and caps any session at 24 hours.
+func PresetSessionStore() LRUCacheConfig {
+	return LRUCacheConfig{
+		ItemLimit: 100_000,
+		TTL:       24 * 60 * 60 * 1000,
+		IdleTTL:   30 * 60 * 1000,
+		TTLMode:   AbsoluteTTL,
+	}
+}
+
+// PresetAPIMemoization caches responses for a minute after they were
+// fetched, no matter how often they are read.
+func PresetAPIMemoization() LRUCacheConfig {
+	return LRUCacheConfig{
+		ItemLimit: 10_000,
+		TTL:       60 * 1000,
+		TTLMode:   AbsoluteTTL,
+	}
+}
+
+// PresetDNS holds resolved records for five minutes after resolution and
+// drops names that haven't been looked up for a minute.
+func PresetDNS() LRUCacheConfig {
+	return LRUCacheConfig{
+		ItemLimit: 10_000,
+		TTL:       5 * 60 * 1000,
+		IdleTTL:   60 * 1000,
+		TTLMode:   AbsoluteTTL,
+	}
+}
@@ -0,0 +1,36 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresets(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+	t.Run("presets build working caches", func(t *testing.T) {
+		for name, config := range map[string]LRUCacheConfig{
+			"session store":   PresetSessionStore(),
+			"API memoization": PresetAPIMemoization(),
+			"DNS":             PresetDNS(),
+		} {
+			lruCache := cacheProvider.NewLRUCache(config)
+			lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+			value, err := lruCache.Get("user1")
+			assert.NoError(t, err, name)
+			assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value, name)
+		}
+	})
+
+	t.Run("presets can be overridden", func(t *testing.T) {
+		config := PresetDNS()
+		config.ItemLimit = 1
+		lruCache := cacheProvider.NewLRUCache(config)
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+		assert.False(t, lruCache.Has("user1"))
+		assert.True(t, lruCache.Has("user2"))
+		assert.Equal(t, int64(10_000), PresetDNS().ItemLimit, "Overrides should not leak into the preset")
+	})
+}
@@ -0,0 +1,56 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCachePromotion(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	fill := func(lruCache LRUCacher[UserData]) {
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+	}
+
+	t.Run("promotes on every Nth access", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2, PromoteEvery: 2})
+		fill(lruCache)
+		lruCache.Get("user1")
+		lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+		assert.False(t, lruCache.Has("user1"), "A single access should not promote 'user1'")
+
+		lruCache = cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2, PromoteEvery: 2})
+		fill(lruCache)
+		lruCache.Get("user1")
+		lruCache.Get("user1")
+		lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+		assert.True(t, lruCache.Has("user1"), "The second access should promote 'user1'")
+		assert.False(t, lruCache.Has("user2"))
+	})
+
+	t.Run("promotes at most once per interval", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2, PromoteInterval: 100})
+		fill(lruCache)
+		lruCache.Get("user1")
+		lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+		assert.False(t, lruCache.Has("user1"), "Access right after Set should not promote again")
+
+		lruCache = cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2, PromoteInterval: 100})
+		fill(lruCache)
+		time.Sleep(150 * time.Millisecond)
+		lruCache.Get("user1")
+		lruCache.Set("user3", UserData{ID: 3, Name: "Charlie", Age: 35})
+		assert.True(t, lruCache.Has("user1"))
+	})
+
+	t.Run("throttled accesses still slide the TTL", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 200, PromoteEvery: 100})
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		for i := 0; i < 3; i++ {
+			time.Sleep(100 * time.Millisecond)
+			assert.True(t, lruCache.Has("user1"))
+		}
+	})
+}
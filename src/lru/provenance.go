@@ -0,0 +1,69 @@
+package lru
+
+import "time"
+
+// Provenance records where a cached value came from, for debugging stale or
+// wrong entries. Values written with a plain Set have none.
+type Provenance struct {
+	Loader string
+	// SourceTier is the 1-based index of the TieredLRUCache tier the value
+	// was read from, zero if it didn't come from a tier.
+	SourceTier   int
+	Node         string
+	LoadDuration time.Duration
+}
+
+type EntryInfo struct {
+	Key        string
+	InsertedAt time.Time
+	WrittenAt  time.Time
+	AccessedAt time.Time
+	// zero if the entry never expires
+	ExpiresAt  time.Time
+	Provenance Provenance
+	ReadOnly   bool
+	// set from Config.RedactKeys, for MarshalJSON
+	redactKey bool
+}
+
+type provenanceWriter[T any] interface {
+	SetWithProvenance(key string, value T, provenance Provenance) T
+}
+
+// SetWithProvenance is like Set, but records provenance for EntryInfo. It
+// counts as a fill rather than a set in Stats.
+func (cache *LRUCache[K, V]) SetWithProvenance(key K, value V, provenance Provenance) V {
+	return cache.set(key, value, &provenance, 0)
+}
+
+// EntryInfo describes a live entry without counting as an access.
+func (cache *LRUCache[K, V]) EntryInfo(key K) (EntryInfo, bool) {
+	var item *StorageItem[V]
+	if safeMap := cache.frozen.Load(); safeMap != nil {
+		item = (*safeMap)[key]
+	} else {
+		cache.init()
+		cache.Storage.mu.RLock()
+		defer cache.Storage.mu.RUnlock()
+		item = cache.Storage.SafeMap[key]
+		if item != nil && cache.expired(item, cache.now()) {
+			item = nil
+		}
+	}
+	if item == nil {
+		return EntryInfo{}, false
+	}
+	info := EntryInfo{
+		Key:        keyString(key),
+		InsertedAt: item.InsertedAt,
+		WrittenAt:  item.WrittenAt,
+		AccessedAt: item.AccessedAt,
+		ExpiresAt:  item.DeleteAt,
+		ReadOnly:   item.readOnly,
+		redactKey:  cache.Config.RedactKeys,
+	}
+	if item.provenance != nil {
+		info.Provenance = *item.provenance
+	}
+	return info, true
+}
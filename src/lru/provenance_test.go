@@ -0,0 +1,52 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntryInfo(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+	t.Run("describes live entries", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+
+		info, ok := lruCache.EntryInfo("user1")
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Second), info.ExpiresAt, 100*time.Millisecond)
+		assert.Equal(t, Provenance{}, info.Provenance, "Plain Set should have no provenance")
+
+		_, ok = lruCache.EntryInfo("user2")
+		assert.False(t, ok)
+	})
+
+	t.Run("keeps provenance until the entry is overwritten", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.SetWithProvenance("user1", UserData{ID: 1, Name: "Alice", Age: 30}, Provenance{Loader: "users-db"})
+
+		info, _ := lruCache.EntryInfo("user1")
+		assert.Equal(t, "users-db", info.Provenance.Loader)
+
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 31})
+		info, _ = lruCache.EntryInfo("user1")
+		assert.Equal(t, Provenance{}, info.Provenance)
+	})
+
+	t.Run("records the source tier of promoted entries", func(t *testing.T) {
+		upper := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lower := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		lruCache := &TieredLRUCache[UserData]{Tiers: []LRUCacher[UserData]{upper, lower}, Node: "node-a"}
+
+		lower.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		_, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+
+		info, ok := upper.EntryInfo("user1")
+		assert.True(t, ok)
+		assert.Equal(t, 2, info.Provenance.SourceTier)
+		assert.Equal(t, "node-a", info.Provenance.Node)
+	})
+}
@@ -0,0 +1,70 @@
+package lru
+
+// SetReadOnly is like Set, but the entry can't be replaced until it
+// expires, is deleted or evicted, or the whole cache is cleared or swapped.
+// Set on a read-only entry is dropped, or panics with ErrReadOnly under
+// PanicOnFrozenWrites, and Patch returns ErrReadOnly; either way the write
+// is counted in Stats.ReadOnlyRejections. SetReadOnly itself returns
+// ErrReadOnly if key already holds a read-only entry, and ErrCacheFrozen if
+// the cache is frozen.
+func (cache *LRUCache[K, V]) SetReadOnly(key K, value V) error {
+	return cache.trySet(key, value, true)
+}
+
+// TrySet is Set for callers that need to know whether the value was
+// stored, such as writers to a WriteOnce cache: it returns ErrReadOnly if
+// key holds a read-only entry, ErrCacheFrozen if the cache is frozen, and
+// with ValidateOnSet, errors from Hooks.Validate.
+func (cache *LRUCache[K, V]) TrySet(key K, value V) error {
+	return cache.trySet(key, value, false)
+}
+
+func (cache *LRUCache[K, V]) trySet(key K, value V, readOnly bool) error {
+	if cache.Config.ValidateOnSet {
+		if err := cache.validate(key, value); err != nil {
+			return err
+		}
+	}
+	cache.init()
+	size := cache.weigh(key, value)
+	var evicted []Entry[K, V]
+	defer func() {
+		cache.notifyRemoved()
+		cache.notifyEvicted(evicted, CapacityEvicted)
+	}()
+	cache.Storage.mu.Lock()
+	defer cache.Storage.mu.Unlock()
+	if cache.rejectFrozenWrite() {
+		return ErrCacheFrozen
+	}
+	key = cache.resolve(key)
+	if cache.readOnly(key) {
+		cache.stats.readOnlyRejections.Add(1)
+		return ErrReadOnly
+	}
+	evicted = cache.store(key, value, nil, 0, size)
+	if readOnly {
+		cache.Storage.SafeMap[key].readOnly = true
+	}
+	return nil
+}
+
+// readOnly reports whether key holds a live read-only entry. Callers must
+// hold the lock.
+func (cache *LRUCache[K, V]) readOnly(key K) bool {
+	item, exists := cache.Storage.SafeMap[key]
+	return exists && item.readOnly && !cache.expired(item, cache.now())
+}
+
+// rejectReadOnlyWrite is rejectFrozenWrite for a single read-only entry.
+// Callers must hold the write lock.
+func (cache *LRUCache[K, V]) rejectReadOnlyWrite(key K) bool {
+	if !cache.readOnly(key) {
+		return false
+	}
+	cache.stats.readOnlyRejections.Add(1)
+	if cache.Config.FrozenWrites == PanicOnFrozenWrites {
+		panic(ErrReadOnly)
+	}
+	return true
+}
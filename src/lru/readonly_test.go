@@ -0,0 +1,87 @@
+package lru
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheReadOnly(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	alice := UserData{ID: 1, Name: "Alice", Age: 30}
+	bob := UserData{ID: 2, Name: "Bob", Age: 25}
+
+	t.Run("keeps the value through Set, Patch and SetReadOnly", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		assert.NoError(t, lruCache.SetReadOnly("user1", alice))
+
+		lruCache.Set("user1", bob)
+		lruCache.SetWithTTL("user1", bob, time.Minute)
+		lruCache.SetWithProvenance("user1", bob, Provenance{Loader: "db"})
+		assert.ErrorIs(t, lruCache.Patch("user1", func(user *UserData) { user.Age++ }), ErrReadOnly)
+		assert.ErrorIs(t, lruCache.SetReadOnly("user1", bob), ErrReadOnly)
+
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, alice, value)
+		actual, loaded := lruCache.GetOrSet("user1", bob)
+		assert.True(t, loaded)
+		assert.Equal(t, alice, actual)
+		info, _ := lruCache.EntryInfo("user1")
+		assert.True(t, info.ReadOnly)
+	})
+
+	t.Run("can be written again once deleted or expired", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 100}).(*InMemoryLRUCache[UserData])
+		assert.NoError(t, lruCache.SetReadOnly("user1", alice))
+		assert.True(t, lruCache.Delete("user1"))
+		lruCache.Set("user1", bob)
+		value, _ := lruCache.Get("user1")
+		assert.Equal(t, bob, value)
+
+		assert.NoError(t, lruCache.SetReadOnly("user2", alice))
+		time.Sleep(150 * time.Millisecond)
+		lruCache.Set("user2", bob)
+		value, _ = lruCache.Get("user2")
+		assert.Equal(t, bob, value)
+	})
+
+	t.Run("only affects the read-only entry", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		assert.NoError(t, lruCache.SetReadOnly("user1", alice))
+		lruCache.Set("user2", alice)
+		lruCache.Set("user2", bob)
+		assert.NoError(t, lruCache.Patch("user2", func(user *UserData) { user.Age++ }))
+		value, _ := lruCache.Get("user2")
+		assert.Equal(t, 26, value.Age)
+	})
+
+	t.Run("panics on writes when configured to", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, FrozenWrites: PanicOnFrozenWrites}).(*InMemoryLRUCache[UserData])
+		assert.NoError(t, lruCache.SetReadOnly("user1", alice))
+		assert.PanicsWithValue(t, ErrReadOnly, func() { lruCache.Set("user1", bob) })
+		assert.PanicsWithValue(t, ErrReadOnly, func() { lruCache.Patch("user1", func(user *UserData) {}) })
+		assert.ErrorIs(t, lruCache.SetReadOnly("user1", bob), ErrReadOnly)
+	})
+
+	t.Run("survives snapshots", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		assert.NoError(t, lruCache.SetReadOnly("user1", alice))
+		var buf bytes.Buffer
+		assert.NoError(t, lruCache.SaveTo(&buf))
+
+		restored := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		assert.NoError(t, restored.LoadFrom(&buf))
+		restored.Set("user1", bob)
+		value, _ := restored.Get("user1")
+		assert.Equal(t, alice, value)
+	})
+
+	t.Run("returns ErrCacheFrozen on frozen caches", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Freeze()
+		assert.ErrorIs(t, lruCache.SetReadOnly("user1", alice), ErrCacheFrozen)
+	})
+}
@@ -0,0 +1,69 @@
+package lru
+
+import "errors"
+
+// ErrKeyExists is returned by Rename when the new key is taken.
+var ErrKeyExists = errors.New("key already exists on LRU cache")
+
+// Rename atomically moves the entry of oldKey to newKey, keeping its value,
+// deadlines, recency and flags. If newKey holds a live entry, Rename returns
+// ErrKeyExists unless overwrite is set, in which case that entry is dropped,
+// unless it is read-only. Renaming a missing key is an error; renaming a key
+// to itself does nothing.
+func (cache *LRUCache[K, V]) Rename(oldKey, newKey K, overwrite bool) error {
+	cache.init()
+	defer cache.notifyRemoved()
+	cache.Storage.mu.Lock()
+	defer cache.Storage.mu.Unlock()
+	if cache.rejectFrozenWrite() {
+		return ErrCacheFrozen
+	}
+	now := cache.now()
+	item, exists := cache.Storage.SafeMap[oldKey]
+	if !exists {
+		return ErrKeyNotFound
+	}
+	if cache.expired(item, now) {
+		cache.expireKey(oldKey, now)
+		return ErrKeyExpired
+	}
+	if oldKey == newKey {
+		return nil
+	}
+	if target, exists := cache.Storage.SafeMap[newKey]; exists && !cache.expired(target, now) {
+		if !overwrite {
+			return ErrKeyExists
+		}
+		if target.readOnly {
+			return ErrReadOnly
+		}
+	}
+	cache.expireKey(newKey, now)
+	cache.forgetVictim(newKey, Replaced)
+	if target, exists := cache.Storage.SafeMap[newKey]; exists {
+		cache.recordRemoval(newKey, target.Value, Replaced)
+		cache.deleteKey(newKey)
+	}
+	cache.dropAliases(newKey)
+	cache.unalias(newKey)
+	cache.renameAliases(oldKey, newKey)
+
+	cache.wheel.remove(oldKey, item.expiryTick)
+	if cache.index != nil {
+		cache.index.remove(any(oldKey).(string))
+		cache.index.insert(any(newKey).(string))
+	}
+	if cache.policy != nil {
+		cache.policy.renamed(oldKey, newKey)
+	}
+	item.element.Value = newKey
+	delete(cache.Storage.SafeMap, oldKey)
+	cache.Storage.SafeMap[newKey] = item
+	if cache.published.Load() != nil {
+		cache.unpublish(oldKey)
+		cache.publish(newKey, item)
+		cache.publishAliases(newKey)
+	}
+	item.expiryTick = cache.wheel.schedule(newKey, 0, item.DeleteAt)
+	return nil
+}
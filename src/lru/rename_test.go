@@ -0,0 +1,116 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheRename(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	alice := UserData{ID: 1, Name: "Alice", Age: 30}
+	bob := UserData{ID: 2, Name: "Bob", Age: 25}
+
+	t.Run("moves the value, deadline and recency", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.SetWithTTL("user1", alice, time.Minute)
+		lruCache.Set("user2", bob)
+		before, _ := lruCache.EntryInfo("user1")
+
+		assert.NoError(t, lruCache.Rename("user1", "alice", false))
+		assert.False(t, lruCache.Has("user1"))
+		assert.Equal(t, []string{"user2", "alice"}, lruCache.Keys())
+		after, exists := lruCache.EntryInfo("alice")
+		assert.True(t, exists)
+		assert.Equal(t, before.ExpiresAt, after.ExpiresAt)
+		value, err := lruCache.Get("alice")
+		assert.NoError(t, err)
+		assert.Equal(t, alice, value)
+	})
+
+	t.Run("keeps the renamed key expiring", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 100}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		assert.NoError(t, lruCache.Rename("user1", "alice", false))
+		time.Sleep(150 * time.Millisecond)
+		assert.False(t, lruCache.Has("alice"))
+		assert.ErrorIs(t, lruCache.Rename("alice", "user1", false), ErrKeyNotFound)
+	})
+
+	t.Run("only replaces a taken key when asked to", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", bob)
+		assert.ErrorIs(t, lruCache.Rename("user1", "user2", false), ErrKeyExists)
+		value, _ := lruCache.Get("user2")
+		assert.Equal(t, bob, value)
+
+		assert.NoError(t, lruCache.Rename("user1", "user2", true))
+		value, _ = lruCache.Get("user2")
+		assert.Equal(t, alice, value)
+		assert.Equal(t, 1, lruCache.Len())
+	})
+
+	t.Run("refuses to replace read-only entries", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		assert.NoError(t, lruCache.SetReadOnly("user2", bob))
+		assert.ErrorIs(t, lruCache.Rename("user1", "user2", true), ErrReadOnly)
+
+		assert.NoError(t, lruCache.Rename("user2", "bob", false))
+		info, _ := lruCache.EntryInfo("bob")
+		assert.True(t, info.ReadOnly)
+	})
+
+	t.Run("returns errors for missing keys and frozen caches", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		assert.Error(t, lruCache.Rename("user1", "alice", false))
+		lruCache.Set("user1", alice)
+		assert.NoError(t, lruCache.Rename("user1", "user1", false))
+		lruCache.Freeze()
+		assert.ErrorIs(t, lruCache.Rename("user1", "alice", false), ErrCacheFrozen)
+	})
+
+	t.Run("keeps the place of the entry in every policy", func(t *testing.T) {
+		for _, eviction := range []EvictionPolicy{LRUEviction, TwoQueueEviction, FIFOEviction, ClockEviction} {
+			lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 3, Eviction: eviction}).(*InMemoryLRUCache[UserData])
+			lruCache.Set("user1", alice)
+			lruCache.Set("user2", bob)
+			lruCache.Set("user3", bob)
+			preview := lruCache.PreviewEvictions(3)
+			assert.NoError(t, lruCache.Rename("user1", "alice", false))
+			for i, key := range preview {
+				if key == "user1" {
+					preview[i] = "alice"
+				}
+			}
+			assert.Equal(t, preview, lruCache.PreviewEvictions(3), eviction.String())
+
+			lruCache.Set("user4", bob)
+			assert.False(t, lruCache.Has(preview[0]), eviction.String())
+		}
+	})
+
+	t.Run("works behind TinyLFU", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 3, Admission: TinyLFUAdmission}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", bob)
+		assert.NoError(t, lruCache.Rename("user1", "alice", false))
+		assert.NoError(t, lruCache.Rename("user2", "bob", false))
+		for _, key := range []string{"user3", "user4", "user5"} {
+			lruCache.Set(key, bob)
+		}
+		assert.Equal(t, 3, lruCache.Len())
+		assert.ElementsMatch(t, lruCache.Keys(), lruCache.PreviewEvictions(3))
+	})
+
+	t.Run("updates the key index", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, PrefixIndex: true}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user:1", alice)
+		lruCache.Set("user:2", bob)
+		assert.NoError(t, lruCache.Rename("user:1", "admin:1", false))
+		assert.Equal(t, 1, lruCache.CountPrefix("user:"))
+		assert.Equal(t, 1, lruCache.CountPrefix("admin:"))
+	})
+}
@@ -0,0 +1,75 @@
+package lru
+
+import "time"
+
+// startRevalidation reports whether item is due for Hooks.Revalidate and
+// marks it, so concurrent Gets don't check it as well. Callers must hold the
+// write lock.
+func (cache *LRUCache[K, V]) startRevalidation(item *StorageItem[V], now time.Time) bool {
+	if cache.Config.RevalidateAfter <= 0 || cache.Hooks.Revalidate == nil || item.revalidating || item.readOnly {
+		return false
+	}
+	if now.Sub(item.validatedAt) < time.Duration(cache.Config.RevalidateAfter)*time.Millisecond {
+		return false
+	}
+	item.revalidating = true
+	return true
+}
+
+// revalidate runs Hooks.Revalidate on item and returns the value Get should
+// return. Fresh values go through Hooks.Validate and count as fills. If
+// either fails, the current value is kept and checked again after another
+// RevalidateAfter, so a failing backing store isn't asked on every Get.
+func (cache *LRUCache[K, V]) revalidate(key K, item *StorageItem[V]) V {
+	value := item.Value
+	// fresh and changed are only to be read if the hook returned in time
+	var fresh V
+	var changed bool
+	var hookErr error
+	err := cache.guard("Revalidate", func() { fresh, changed, hookErr = cache.Hooks.Revalidate(key, value) })
+	if err == nil {
+		err = hookErr
+	}
+	if err == nil && changed {
+		err = cache.validate(key, fresh)
+	}
+	if err != nil {
+		cache.stats.errorFallbacks.Add(1)
+		cache.logErrorFallback(key, err)
+	}
+	var size int64
+	if err == nil && changed {
+		size = cache.weigh(key, fresh)
+	}
+
+	defer cache.notifyRemoved()
+	cache.Storage.mu.Lock()
+	defer cache.Storage.mu.Unlock()
+	item.revalidating = false
+	if cache.Storage.SafeMap[key] != item || cache.frozen.Load() != nil {
+		// replaced or deleted in the meantime, nothing to update
+		if err == nil && changed {
+			return fresh
+		}
+		return value
+	}
+	now := cache.now()
+	if err != nil || !changed {
+		item.validatedAt = now
+		return value
+	}
+
+	refreshed := *item
+	refreshed.Value = fresh
+	refreshed.size = size
+	refreshed.validatedAt = now
+	if cache.expires() {
+		refreshed.WrittenAt = now
+		refreshed.bumpDeleteAt(cache.Config, now)
+		refreshed.expiryTick = cache.wheel.schedule(key, item.expiryTick, refreshed.DeleteAt)
+	}
+	cache.Storage.SafeMap[key] = &refreshed
+	cache.recordRemoval(key, value, Replaced)
+	cache.stats.fills.Add(1)
+	return fresh
+}
@@ -0,0 +1,188 @@
+package lru
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type versionedUser struct {
+	Version int
+	Name    string
+}
+
+func TestLRUCacheRevalidate(t *testing.T) {
+	newCache := func(revalidate func(key string, user versionedUser) (versionedUser, bool, error)) *InMemoryLRUCache[versionedUser] {
+		cacheProvider := InMemoryLRUCacheProvider[versionedUser]{Hooks: Hooks[string, versionedUser]{Revalidate: revalidate}}
+		return cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, RevalidateAfter: 100}).(*InMemoryLRUCache[versionedUser])
+	}
+
+	t.Run("only checks entries older than RevalidateAfter", func(t *testing.T) {
+		var checks atomic.Int32
+		lruCache := newCache(func(key string, user versionedUser) (versionedUser, bool, error) {
+			checks.Add(1)
+			return user, false, nil
+		})
+		lruCache.Set("user1", versionedUser{Version: 1, Name: "Alice"})
+
+		lruCache.Get("user1")
+		assert.Equal(t, int32(0), checks.Load())
+
+		time.Sleep(150 * time.Millisecond)
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, versionedUser{Version: 1, Name: "Alice"}, value)
+		assert.Equal(t, int32(1), checks.Load())
+
+		lruCache.Get("user1")
+		assert.Equal(t, int32(1), checks.Load(), "A current entry should not be checked again right away")
+	})
+
+	t.Run("refreshes entries whose version changed", func(t *testing.T) {
+		lruCache := newCache(func(key string, user versionedUser) (versionedUser, bool, error) {
+			if user.Version == 2 {
+				return user, false, nil
+			}
+			return versionedUser{Version: 2, Name: "Alice Updated"}, true, nil
+		})
+		lruCache.Set("user1", versionedUser{Version: 1, Name: "Alice"})
+
+		time.Sleep(150 * time.Millisecond)
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, versionedUser{Version: 2, Name: "Alice Updated"}, value)
+		value, _ = lruCache.Peek("user1")
+		assert.Equal(t, 2, value.Version)
+		assert.Equal(t, uint64(1), lruCache.Stats().Fills)
+	})
+
+	t.Run("keeps serving the cached value when the check fails", func(t *testing.T) {
+		var checks atomic.Int32
+		lruCache := newCache(func(key string, user versionedUser) (versionedUser, bool, error) {
+			checks.Add(1)
+			return versionedUser{}, false, errors.New("backend down")
+		})
+		lruCache.Set("user1", versionedUser{Version: 1, Name: "Alice"})
+
+		time.Sleep(150 * time.Millisecond)
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Alice", value.Name)
+		lruCache.Get("user1")
+		assert.Equal(t, int32(1), checks.Load(), "Failed checks should back off for RevalidateAfter")
+	})
+
+	t.Run("concurrent Gets share one check", func(t *testing.T) {
+		var checks atomic.Int32
+		lruCache := newCache(func(key string, user versionedUser) (versionedUser, bool, error) {
+			checks.Add(1)
+			time.Sleep(50 * time.Millisecond)
+			return user, false, nil
+		})
+		lruCache.Set("user1", versionedUser{Version: 1, Name: "Alice"})
+		time.Sleep(150 * time.Millisecond)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, err := lruCache.Get("user1")
+				assert.NoError(t, err)
+				assert.Equal(t, "Alice", value.Name)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), checks.Load())
+	})
+
+	t.Run("classifies hits by freshness", func(t *testing.T) {
+		var fail atomic.Bool
+		checking := make(chan struct{})
+		release := make(chan struct{})
+		lruCache := newCache(func(key string, user versionedUser) (versionedUser, bool, error) {
+			if fail.Load() {
+				return versionedUser{}, false, errors.New("backend down")
+			}
+			close(checking)
+			<-release
+			return user, false, nil
+		})
+		lruCache.Set("user1", versionedUser{Version: 1, Name: "Alice"})
+		lruCache.Get("user1")
+		lruCache.Get("user2")
+		time.Sleep(150 * time.Millisecond)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			lruCache.Get("user1")
+		}()
+		<-checking
+		lruCache.Get("user1")
+		close(release)
+		<-done
+
+		fail.Store(true)
+		time.Sleep(150 * time.Millisecond)
+		lruCache.Get("user1")
+
+		stats := lruCache.Stats()
+		assert.Equal(t, uint64(4), stats.Hits)
+		assert.Equal(t, uint64(2), stats.FreshHits, "The first read and the one that checked the entry")
+		assert.Equal(t, uint64(1), stats.StaleHits, "The read served while the check was running")
+		assert.Equal(t, uint64(1), stats.ErrorFallbacks)
+		assert.Equal(t, uint64(1), stats.Misses)
+		assert.Equal(t, uint64(1), lruCache.StatsDelta().StaleHits)
+		assert.Equal(t, uint64(0), lruCache.StatsDelta().StaleHits)
+	})
+
+	t.Run("checks patched entries again", func(t *testing.T) {
+		clock := newFakeClock()
+		var checks atomic.Int32
+		checking, release := make(chan struct{}), make(chan struct{})
+		lruCache := &InMemoryLRUCache[versionedUser]{Config: LRUCacheConfig{ItemLimit: 10, RevalidateAfter: 100}, clock: clock.Now}
+		lruCache.Hooks.Revalidate = func(key string, user versionedUser) (versionedUser, bool, error) {
+			if checks.Add(1) == 1 {
+				checking <- struct{}{}
+				<-release
+			}
+			return user, false, nil
+		}
+		assert.NoError(t, lruCache.Close())
+		lruCache.Set("user1", versionedUser{Version: 1, Name: "Alice"})
+		clock.Advance(150 * time.Millisecond)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			lruCache.Get("user1")
+		}()
+		<-checking
+		assert.NoError(t, lruCache.Patch("user1", func(user *versionedUser) { user.Version++ }))
+		close(release)
+		<-done
+
+		clock.Advance(150 * time.Millisecond)
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, value.Version)
+		assert.Equal(t, int32(2), checks.Load(), "The patched entry should be due for a check again")
+	})
+
+	t.Run("is disabled without RevalidateAfter", func(t *testing.T) {
+		cacheProvider := InMemoryLRUCacheProvider[versionedUser]{Hooks: Hooks[string, versionedUser]{
+			Revalidate: func(key string, user versionedUser) (versionedUser, bool, error) {
+				t.Error("Revalidate should not be called")
+				return user, false, nil
+			},
+		}}
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		lruCache.Set("user1", versionedUser{Version: 1, Name: "Alice"})
+		lruCache.Get("user1")
+	})
+}
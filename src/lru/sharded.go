@@ -0,0 +1,164 @@
+package lru
+
+import (
+	"errors"
+	"hash/maphash"
+	"runtime"
+)
+
+// ShardedLRUCacheProvider splits the keyspace over independent
+// InMemoryLRUCaches, each with its own lock and sweeper, so parallel callers
+// rarely wait on each other. Recency, and with it eviction, is tracked per
+// shard: ItemLimit is divided evenly between the shards, and a shard may
+// evict while others still have room.
+type ShardedLRUCacheProvider[T any] struct {
+	// Shards is the number of shards, 4 per GOMAXPROCS if zero, and at most
+	// ItemLimit, so every shard can hold an entry.
+	Shards int
+	Hooks  Hooks[string, T]
+}
+
+func (cacheProvider ShardedLRUCacheProvider[T]) NewLRUCache(config LRUCacheConfig) LRUCacher[T] {
+	n := cacheProvider.Shards
+	if n <= 0 {
+		n = 4 * runtime.GOMAXPROCS(0)
+	}
+	if config.ItemLimit > 0 && int64(n) > config.ItemLimit {
+		n = int(config.ItemLimit)
+	}
+	shardConfig := config
+	// published once for all shards, below
+	shardConfig.ExpvarName = ""
+	cache := &ShardedLRUCache[T]{config: config, seed: maphash.MakeSeed(), shards: make([]*InMemoryLRUCache[T], n)}
+	for i := range cache.shards {
+		if config.ItemLimit > 0 {
+			// the first shards take the remainder, so the limits add up
+			shardConfig.ItemLimit = config.ItemLimit / int64(n)
+			if int64(i) < config.ItemLimit%int64(n) {
+				shardConfig.ItemLimit++
+			}
+		}
+		cache.shards[i] = InMemoryLRUCacheProvider[T]{Hooks: cacheProvider.Hooks}.NewLRUCache(shardConfig).(*InMemoryLRUCache[T])
+	}
+	if config.ExpvarName != "" {
+		PublishExpvar(config.ExpvarName, cache)
+	}
+	return cache
+}
+
+type ShardedLRUCache[T any] struct {
+	config LRUCacheConfig
+	seed   maphash.Seed
+	shards []*InMemoryLRUCache[T]
+}
+
+func (cache *ShardedLRUCache[T]) shard(key string) *InMemoryLRUCache[T] {
+	return cache.shards[maphash.String(cache.seed, key)%uint64(len(cache.shards))]
+}
+
+func (cache *ShardedLRUCache[T]) Has(key string) bool {
+	return cache.shard(key).Has(key)
+}
+
+func (cache *ShardedLRUCache[T]) Get(key string) (T, error) {
+	return cache.shard(key).Get(key)
+}
+
+func (cache *ShardedLRUCache[T]) GetOK(key string) (T, bool) {
+	return cache.shard(key).GetOK(key)
+}
+
+func (cache *ShardedLRUCache[T]) Set(key string, value T) T {
+	return cache.shard(key).Set(key, value)
+}
+
+func (cache *ShardedLRUCache[T]) Delete(key string) bool {
+	return cache.shard(key).Delete(key)
+}
+
+// Clear clears the shards one after another, so it isn't atomic across
+// the whole cache.
+func (cache *ShardedLRUCache[T]) Clear() {
+	for _, shard := range cache.shards {
+		shard.Clear()
+	}
+}
+
+func (cache *ShardedLRUCache[T]) Len() int {
+	total := 0
+	for _, shard := range cache.shards {
+		total += shard.Len()
+	}
+	return total
+}
+
+func (cache *ShardedLRUCache[T]) Cap() int64 {
+	return cache.config.ItemLimit
+}
+
+// Stats adds up the stats of all shards.
+func (cache *ShardedLRUCache[T]) Stats() Stats {
+	var total Stats
+	for _, shard := range cache.shards {
+		total = addStats(total, shard.Stats())
+	}
+	return total
+}
+
+// StatsDelta adds up the StatsDelta of all shards.
+func (cache *ShardedLRUCache[T]) StatsDelta() Stats {
+	var total Stats
+	for _, shard := range cache.shards {
+		total = addStats(total, shard.StatsDelta())
+	}
+	return total
+}
+
+func (cache *ShardedLRUCache[T]) Close() error {
+	var errs []error
+	for _, shard := range cache.shards {
+		errs = append(errs, shard.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func addStats(total, stats Stats) Stats {
+	total.Hits += stats.Hits
+	total.FreshHits += stats.FreshHits
+	total.StaleHits += stats.StaleHits
+	total.ErrorFallbacks += stats.ErrorFallbacks
+	total.Misses += stats.Misses
+	total.Sets += stats.Sets
+	total.Fills += stats.Fills
+	total.Evictions += stats.Evictions
+	total.PrematureEvictions += stats.PrematureEvictions
+	total.EvictionVetoes += stats.EvictionVetoes
+	total.Expirations += stats.Expirations
+	total.Deletions += stats.Deletions
+	total.Replacements += stats.Replacements
+	total.Cleared += stats.Cleared
+	total.Rejections += stats.Rejections
+	total.ReadOnlyRejections += stats.ReadOnlyRejections
+	total.CallbackPanics += stats.CallbackPanics
+	total.SlowCallbacks += stats.SlowCallbacks
+	total.ExpiryPaused = total.ExpiryPaused || stats.ExpiryPaused
+	total.LastMinute = addWindowStats(total.LastMinute, stats.LastMinute)
+	total.LastFiveMinutes = addWindowStats(total.LastFiveMinutes, stats.LastFiveMinutes)
+	total.LastFifteenMinutes = addWindowStats(total.LastFifteenMinutes, stats.LastFifteenMinutes)
+	return total
+}
+
+// addWindowStats derives MissRatio from the counts rather than the rates:
+// shards are sampled at slightly different times, so their windows don't
+// cover exactly the same span.
+func addWindowStats(total, window WindowStats) WindowStats {
+	total.HitsPerSecond += window.HitsPerSecond
+	total.MissesPerSecond += window.MissesPerSecond
+	total.hits += window.hits
+	total.misses += window.misses
+	total.MissRatio = 0
+	if requests := total.hits + total.misses; requests > 0 {
+		total.MissRatio = float64(total.misses) / float64(requests)
+	}
+	return total
+}
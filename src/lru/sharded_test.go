@@ -0,0 +1,114 @@
+package lru
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedLRUCache(t *testing.T) {
+	cacheProvider := ShardedLRUCacheProvider[UserData]{Shards: 4}
+
+	t.Run("stores and returns values", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 100})
+		assert.False(t, lruCache.Has("user1"))
+
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value)
+		_, err = lruCache.Get("user2")
+		assert.Error(t, err)
+		_, ok := lruCache.(*ShardedLRUCache[UserData]).GetOK("user1")
+		assert.True(t, ok)
+
+		assert.True(t, lruCache.Delete("user1"))
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+		lruCache.Clear()
+		assert.False(t, lruCache.Has("user2"))
+	})
+
+	t.Run("splits ItemLimit across shards", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 40, Logger: slog.New(slog.DiscardHandler)}).(*ShardedLRUCache[UserData])
+		for i := 0; i < 200; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+		}
+		assert.LessOrEqual(t, lruCache.Len(), 40)
+		assert.Equal(t, int64(40), lruCache.Cap())
+		for _, shard := range lruCache.shards {
+			assert.Equal(t, int64(10), shard.Cap())
+		}
+	})
+
+	t.Run("never holds more than ItemLimit", func(t *testing.T) {
+		for _, limit := range []int64{3, 10, 42} {
+			lruCache := ShardedLRUCacheProvider[UserData]{Shards: 16}.NewLRUCache(LRUCacheConfig{ItemLimit: limit, Logger: slog.New(slog.DiscardHandler)}).(*ShardedLRUCache[UserData])
+			for i := 0; i < 500; i++ {
+				lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+			}
+			assert.LessOrEqual(t, int64(lruCache.Len()), lruCache.Cap())
+			total := int64(0)
+			for _, shard := range lruCache.shards {
+				total += shard.Cap()
+			}
+			assert.Equal(t, limit, total)
+		}
+	})
+
+	t.Run("expires entries in every shard", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 100, TTL: 100, Logger: slog.New(slog.DiscardHandler)}).(*ShardedLRUCache[UserData])
+		for i := 0; i < 20; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+		}
+		time.Sleep(250 * time.Millisecond)
+		assert.Zero(t, lruCache.Len())
+		assert.Equal(t, uint64(20), lruCache.Stats().Expirations)
+	})
+
+	t.Run("aggregates stats over shards", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 100}).(*ShardedLRUCache[UserData])
+		for i := 0; i < 10; i++ {
+			key := fmt.Sprintf("user%d", i)
+			lruCache.Set(key, UserData{ID: i})
+			lruCache.Get(key)
+			lruCache.Get(key + "-missing")
+		}
+		stats := lruCache.Stats()
+		assert.Equal(t, uint64(10), stats.Sets)
+		assert.Equal(t, uint64(10), stats.Hits)
+		assert.Equal(t, uint64(10), stats.Misses)
+		assert.InDelta(t, 0.5, stats.LastMinute.MissRatio, 0.01)
+
+		lruCache.Get("user1")
+		assert.Equal(t, uint64(11), lruCache.StatsDelta().Hits)
+		assert.Equal(t, uint64(0), lruCache.StatsDelta().Hits)
+	})
+
+	t.Run("defaults to a GOMAXPROCS-based shard count", func(t *testing.T) {
+		lruCache := ShardedLRUCacheProvider[UserData]{}.NewLRUCache(LRUCacheConfig{}).(*ShardedLRUCache[UserData])
+		assert.GreaterOrEqual(t, len(lruCache.shards), 4)
+		assert.NoError(t, lruCache.Close())
+	})
+
+	t.Run("handles concurrent access", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 50, Logger: slog.New(slog.DiscardHandler)})
+		var wg sync.WaitGroup
+		for worker := 0; worker < 8; worker++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 200; i++ {
+					key := fmt.Sprintf("user%d", (worker+i)%80)
+					lruCache.Set(key, UserData{ID: i})
+					lruCache.Get(key)
+					lruCache.Has(key)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,114 @@
+package lru
+
+import (
+	"encoding/gob"
+	"slices"
+)
+
+// EntrySize is the Hooks.Size of a live entry.
+type EntrySize[K comparable] struct {
+	Key  K
+	Size int64
+}
+
+// SizeStats describes how Hooks.Size is distributed over the live entries.
+// Percentiles are nearest-rank.
+type SizeStats struct {
+	Entries int
+	Total   int64
+	Mean    float64
+	P50     int64
+	P90     int64
+	P99     int64
+	Max     int64
+}
+
+// GobSize is a Hooks.Size that weighs values by their gob encoding, the
+// format SaveTo writes, for values without a cheaper measure. Encoding
+// every value costs about as much as the Set itself. Values gob can't
+// encode weigh zero.
+func GobSize[K comparable, V any](key K, value V) int64 {
+	var counter byteCounter
+	if err := gob.NewEncoder(&counter).Encode(&value); err != nil {
+		return 0
+	}
+	return int64(counter)
+}
+
+type byteCounter int64
+
+func (counter *byteCounter) Write(p []byte) (int, error) {
+	*counter += byteCounter(len(p))
+	return len(p), nil
+}
+
+// weigh runs Hooks.Size, if any. Must be called without holding the lock.
+func (cache *LRUCache[K, V]) weigh(key K, value V) int64 {
+	if cache.Hooks.Size == nil {
+		return 0
+	}
+	var size int64
+	if cache.guard("Size", func() { size = cache.Hooks.Size(key, value) }) != nil {
+		return 0
+	}
+	return size
+}
+
+// entrySizes returns the sizes of the live entries, largest first.
+func (cache *LRUCache[K, V]) entrySizes() []EntrySize[K] {
+	if cache.Hooks.Size == nil {
+		return nil
+	}
+	var sizes []EntrySize[K]
+	if safeMap := cache.frozen.Load(); safeMap != nil {
+		sizes = make([]EntrySize[K], 0, len(*safeMap))
+		for key, item := range *safeMap {
+			sizes = append(sizes, EntrySize[K]{Key: key, Size: item.size})
+		}
+	} else {
+		cache.init()
+		cache.Storage.mu.RLock()
+		now := cache.now()
+		sizes = make([]EntrySize[K], 0, len(cache.Storage.SafeMap))
+		for key, item := range cache.Storage.SafeMap {
+			if !cache.expired(item, now) {
+				sizes = append(sizes, EntrySize[K]{Key: key, Size: item.size})
+			}
+		}
+		cache.Storage.mu.RUnlock()
+	}
+	slices.SortFunc(sizes, func(a, b EntrySize[K]) int {
+		return int(min(max(b.Size-a.Size, -1), 1))
+	})
+	return sizes
+}
+
+// LargestEntries returns the n largest live entries by Hooks.Size, largest
+// first, to find the few values that take up most of the memory. It sorts
+// every entry, so it is meant for diagnostics rather than hot paths.
+// Without Hooks.Size it returns nothing.
+func (cache *LRUCache[K, V]) LargestEntries(n int) []EntrySize[K] {
+	sizes := cache.entrySizes()
+	return sizes[:min(max(n, 0), len(sizes))]
+}
+
+// SizeStats summarizes Hooks.Size over the live entries, at the same cost as
+// LargestEntries. It is zero without Hooks.Size.
+func (cache *LRUCache[K, V]) SizeStats() SizeStats {
+	sizes := cache.entrySizes()
+	if len(sizes) == 0 {
+		return SizeStats{}
+	}
+	stats := SizeStats{Entries: len(sizes), Max: sizes[0].Size}
+	for _, entry := range sizes {
+		stats.Total += entry.Size
+	}
+	stats.Mean = float64(stats.Total) / float64(len(sizes))
+	percentile := func(p int) int64 {
+		// sizes are sorted largest first
+		rank := (len(sizes)*p + 99) / 100
+		return sizes[len(sizes)-rank].Size
+	}
+	stats.P50, stats.P90, stats.P99 = percentile(50), percentile(90), percentile(99)
+	return stats
+}
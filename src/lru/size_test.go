@@ -0,0 +1,65 @@
+package lru
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheEntrySizes(t *testing.T) {
+	newCache := func(config LRUCacheConfig) *LRUCache[string, string] {
+		return InMemoryLRUCacheProvider[string]{Hooks: Hooks[string, string]{
+			Size: func(key string, value string) int64 { return int64(len(value)) },
+		}}.NewLRUCache(config).(*LRUCache[string, string])
+	}
+
+	t.Run("lists the largest entries", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 10})
+		lruCache.Set("small", "a")
+		lruCache.Set("huge", strings.Repeat("a", 1000))
+		lruCache.Set("medium", strings.Repeat("a", 10))
+		assert.Equal(t, []EntrySize[string]{{"huge", 1000}, {"medium", 10}}, lruCache.LargestEntries(2))
+		assert.Len(t, lruCache.LargestEntries(10), 3)
+		assert.Empty(t, lruCache.LargestEntries(0))
+	})
+
+	t.Run("follows updates", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 10})
+		lruCache.Set("user1", "a")
+		lruCache.Set("user2", "ab")
+		assert.NoError(t, lruCache.Patch("user1", func(value *string) { *value = "abc" }))
+		assert.Equal(t, "user1", lruCache.LargestEntries(1)[0].Key)
+		lruCache.Set("user1", "")
+		assert.Equal(t, "user2", lruCache.LargestEntries(1)[0].Key)
+		lruCache.SwapAll(map[string]string{"user3": "abcd"})
+		assert.Equal(t, []EntrySize[string]{{"user3", 4}}, lruCache.LargestEntries(5))
+	})
+
+	t.Run("summarizes the distribution", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 100})
+		for i := 1; i <= 100; i++ {
+			lruCache.Set(fmt.Sprint(i), strings.Repeat("a", i))
+		}
+		assert.Equal(t, SizeStats{Entries: 100, Total: 5050, Mean: 50.5, P50: 50, P90: 90, P99: 99, Max: 100}, lruCache.SizeStats())
+		lruCache.Freeze()
+		assert.Equal(t, int64(5050), lruCache.SizeStats().Total)
+	})
+
+	t.Run("reports nothing without a Size hook", func(t *testing.T) {
+		lruCache := InMemoryLRUCacheProvider[string]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*LRUCache[string, string])
+		lruCache.Set("user1", "a")
+		assert.Empty(t, lruCache.LargestEntries(1))
+		assert.Equal(t, SizeStats{}, lruCache.SizeStats())
+	})
+
+	t.Run("GobSize measures the encoded value", func(t *testing.T) {
+		small := GobSize("user1", UserData{ID: 1, Name: "Al"})
+		large := GobSize("user1", UserData{ID: 1, Name: strings.Repeat("a", 1000)})
+		assert.Greater(t, small, int64(0))
+		// length prefixes grow too
+		assert.InDelta(t, 998, large-small, 8)
+		assert.Equal(t, int64(0), GobSize("user1", func() {}))
+	})
+}
@@ -0,0 +1,108 @@
+package lru
+
+import "container/list"
+
+const (
+	defaultProbationaryRatio = 0.2
+	defaultProtectedRatio    = 0.8
+)
+
+// segmentedLRU implements SegmentedLRUEviction.
+type segmentedLRU[K comparable] struct {
+	protectedLimit int
+	// newest first, both LRU
+	probation *list.List
+	protected *list.List
+	segments  map[K]slruSlot
+}
+
+type slruSlot struct {
+	element   *list.Element
+	protected bool
+}
+
+func newSegmentedLRU[K comparable](config LRUCacheConfig) *segmentedLRU[K] {
+	probationary, protected := config.ProbationaryRatio, config.ProtectedRatio
+	if probationary <= 0 {
+		probationary = defaultProbationaryRatio
+	}
+	if protected <= 0 {
+		protected = defaultProtectedRatio
+	}
+	share := protected / (probationary + protected)
+	return &segmentedLRU[K]{
+		protectedLimit: max(1, int(float64(config.ItemLimit)*share)),
+		probation:      list.New(),
+		protected:      list.New(),
+		segments:       make(map[K]slruSlot),
+	}
+}
+
+func (policy *segmentedLRU[K]) added(key K) {
+	policy.segments[key] = slruSlot{element: policy.probation.PushFront(key)}
+}
+
+// accessed promotes probationary keys. A full protected segment makes room
+// by demoting its least recently used key, which gets another chance in
+// probation rather than being evicted.
+func (policy *segmentedLRU[K]) accessed(key K) {
+	slot, exists := policy.segments[key]
+	if !exists {
+		return
+	}
+	if slot.protected {
+		policy.protected.MoveToFront(slot.element)
+		return
+	}
+	policy.probation.Remove(slot.element)
+	policy.segments[key] = slruSlot{element: policy.protected.PushFront(key), protected: true}
+	if policy.protected.Len() > policy.protectedLimit {
+		demoted := policy.protected.Remove(policy.protected.Back()).(K)
+		policy.segments[demoted] = slruSlot{element: policy.probation.PushFront(demoted)}
+	}
+}
+
+func (policy *segmentedLRU[K]) removed(key K) {
+	slot, exists := policy.segments[key]
+	if !exists {
+		return
+	}
+	if slot.protected {
+		policy.protected.Remove(slot.element)
+	} else {
+		policy.probation.Remove(slot.element)
+	}
+	delete(policy.segments, key)
+}
+
+func (policy *segmentedLRU[K]) renamed(oldKey, newKey K) {
+	slot, exists := policy.segments[oldKey]
+	if !exists {
+		return
+	}
+	slot.element.Value = newKey
+	policy.segments[newKey] = slot
+	delete(policy.segments, oldKey)
+}
+
+func (policy *segmentedLRU[K]) victim(skip func(key K) bool) (K, bool) {
+	for _, segment := range []*list.List{policy.probation, policy.protected} {
+		if oldest := oldestUnskipped(segment, skip); oldest != nil {
+			key := oldest.Value.(K)
+			policy.removed(key)
+			return key, true
+		}
+	}
+	var zero K
+	return zero, false
+}
+
+func (policy *segmentedLRU[K]) victims(n int) []K {
+	keys := make([]K, 0, min(n, len(policy.segments)))
+	for _, segment := range []*list.List{policy.probation, policy.protected} {
+		for element := segment.Back(); element != nil && len(keys) < n; element = element.Prev() {
+			keys = append(keys, element.Value.(K))
+		}
+	}
+	return keys
+}
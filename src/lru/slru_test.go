@@ -0,0 +1,78 @@
+package lru
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegmentedLRUEviction(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	config := LRUCacheConfig{ItemLimit: 5, Eviction: SegmentedLRUEviction, Logger: slog.New(slog.DiscardHandler)}
+
+	t.Run("evicts keys used once before keys used again", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+		for i := 0; i < 5; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+		}
+		lruCache.Get("user0")
+		assert.Equal(t, []string{"user1", "user2", "user3", "user4", "user0"}, lruCache.PreviewEvictions(5))
+
+		lruCache.Set("user5", UserData{ID: 5})
+		assert.True(t, lruCache.Has("user0"))
+		assert.False(t, lruCache.Has("user1"))
+	})
+
+	t.Run("keeps the hot set through a scan", func(t *testing.T) {
+		scanConfig := config
+		scanConfig.ItemLimit = 20
+		lruCache := cacheProvider.NewLRUCache(scanConfig).(*InMemoryLRUCache[UserData])
+		hot := []string{"hot0", "hot1", "hot2", "hot3"}
+		for _, key := range hot {
+			lruCache.Set(key, UserData{})
+			lruCache.Get(key)
+		}
+		for i := 0; i < 100; i++ {
+			lruCache.Set(fmt.Sprintf("scan%d", i), UserData{ID: i})
+		}
+		for _, key := range hot {
+			assert.True(t, lruCache.Has(key), "Key '%s' should have survived the scan", key)
+		}
+	})
+
+	t.Run("demotes protected keys to probation when the segment is full", func(t *testing.T) {
+		segmentConfig := config
+		segmentConfig.ProbationaryRatio = 3
+		segmentConfig.ProtectedRatio = 2
+		lruCache := cacheProvider.NewLRUCache(segmentConfig).(*InMemoryLRUCache[UserData])
+		for i := 0; i < 5; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+		}
+		lruCache.Get("user0")
+		lruCache.Get("user1")
+		lruCache.Get("user2")
+		assert.Equal(t, []string{"user3", "user4", "user0", "user1", "user2"}, lruCache.PreviewEvictions(5),
+			"Only two keys fit the protected segment, so user0 went back to probation")
+
+		lruCache.Get("user0")
+		assert.Equal(t, []string{"user3", "user4", "user1", "user2", "user0"}, lruCache.PreviewEvictions(5))
+	})
+
+	t.Run("works behind TinyLFU", func(t *testing.T) {
+		tinyConfig := config
+		tinyConfig.Admission = TinyLFUAdmission
+		lruCache := cacheProvider.NewLRUCache(tinyConfig).(*InMemoryLRUCache[UserData])
+		for i := 0; i < 20; i++ {
+			key := fmt.Sprintf("user%d", i)
+			lruCache.Set(key, UserData{ID: i})
+			assert.True(t, lruCache.Has(key))
+		}
+		assert.Equal(t, 5, lruCache.Len())
+	})
+
+	t.Run("has a name", func(t *testing.T) {
+		assert.Equal(t, "slru", SegmentedLRUEviction.String())
+	})
+}
@@ -0,0 +1,181 @@
+package lru
+
+import (
+	"container/list"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const snapshotVersion = 1
+
+type snapshotHeader struct {
+	Version int
+	Count   int
+}
+
+// Ages and the remaining TTL are stored relative to the time of the
+// snapshot, so a restored entry expires as if the cache never stopped.
+type snapshotEntry[K comparable, V any] struct {
+	Key         K
+	Value       V
+	InsertedAge time.Duration
+	WrittenAge  time.Duration
+	AccessedAge time.Duration
+	// zero if the entry never expires
+	Remaining time.Duration
+	// set for entries written with SetWithTTL
+	TTL      time.Duration
+	ReadOnly bool
+	// set instead of Key and Value when the snapshot is encrypted
+	KeyID  string
+	Sealed []byte
+}
+
+// SaveTo writes the live entries to w, least recently used first.
+func (cache *LRUCache[K, V]) SaveTo(w io.Writer) error {
+	return cache.saveTo(w, 0)
+}
+
+// SaveHottestTo is like SaveTo but only writes the n most recently used
+// entries, which is usually most of the benefit of a warm restart at a
+// fraction of the cost for large caches.
+func (cache *LRUCache[K, V]) SaveHottestTo(w io.Writer, n int) error {
+	if n <= 0 {
+		return fmt.Errorf("invalid snapshot size %d", n)
+	}
+	return cache.saveTo(w, n)
+}
+
+func (cache *LRUCache[K, V]) saveTo(w io.Writer, limit int) error {
+	entries := cache.snapshotEntries(cache.now())
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	encoder := gob.NewEncoder(w)
+	if err := encoder.Encode(snapshotHeader{Version: snapshotVersion, Count: len(entries)}); err != nil {
+		return fmt.Errorf("writing snapshot header: %w", err)
+	}
+	for _, entry := range entries {
+		key := cache.logKey(entry.Key)
+		if cache.Config.SnapshotKeys != nil {
+			if err := cache.sealSnapshotEntry(&entry); err != nil {
+				return err
+			}
+		}
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("writing snapshot entry %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// snapshotEntries copies the live entries, least recently used first. Only
+// the copy happens under the lock; encoding and I/O don't block the cache.
+func (cache *LRUCache[K, V]) snapshotEntries(now time.Time) []snapshotEntry[K, V] {
+	cache.init()
+	cache.Storage.mu.RLock()
+	defer cache.Storage.mu.RUnlock()
+	entries := make([]snapshotEntry[K, V], 0, len(cache.Storage.SafeMap))
+	for element := cache.order.Back(); element != nil; element = element.Prev() {
+		key := element.Value.(K)
+		item := cache.Storage.SafeMap[key]
+		if cache.expired(item, now) {
+			continue
+		}
+		entry := snapshotEntry[K, V]{Key: key, Value: item.Value, ReadOnly: item.readOnly}
+		if cache.expires() {
+			entry.InsertedAge = now.Sub(item.InsertedAt)
+			entry.WrittenAge = now.Sub(item.WrittenAt)
+			entry.AccessedAge = now.Sub(item.AccessedAt)
+			if !item.DeleteAt.IsZero() {
+				entry.Remaining = item.DeleteAt.Sub(now)
+			}
+			entry.TTL = item.ttl
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// LoadFrom replaces the contents of the cache with a snapshot written by
+// SaveTo, restoring recency order and remaining TTLs. If the snapshot holds
+// more than ItemLimit entries, the least recently used ones are dropped.
+// The entries it replaces are reported to the hooks as SwapAll does.
+func (cache *LRUCache[K, V]) LoadFrom(r io.Reader) error {
+	decoder := gob.NewDecoder(r)
+	var header snapshotHeader
+	if err := decoder.Decode(&header); err != nil {
+		return fmt.Errorf("reading snapshot header: %w", err)
+	}
+	if header.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", header.Version)
+	}
+	if header.Count < 0 {
+		return fmt.Errorf("invalid snapshot entry count %d", header.Count)
+	}
+
+	now := cache.now()
+	// not preallocated, as the count comes from the snapshot
+	var entries []snapshotEntry[K, V]
+	for i := 0; i < header.Count; i++ {
+		var entry snapshotEntry[K, V]
+		if err := decoder.Decode(&entry); err != nil {
+			return fmt.Errorf("reading snapshot entry %d: %w", i, err)
+		}
+		if entry.KeyID != "" {
+			if err := openSnapshotEntry(cache.Config.SnapshotKeys, &entry); err != nil {
+				if errors.Is(err, ErrSnapshotKeyRevoked) {
+					continue
+				}
+				return fmt.Errorf("reading snapshot entry %d: %w", i, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if limit := int(cache.Config.ItemLimit); limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	safeMap := make(map[K]*StorageItem[V], len(entries))
+	order := list.New()
+	entryTTLs := false
+	for _, entry := range entries {
+		item := &StorageItem[V]{
+			Value:      entry.Value,
+			InsertedAt: now.Add(-entry.InsertedAge),
+			WrittenAt:  now.Add(-entry.WrittenAge),
+			AccessedAt: now.Add(-entry.AccessedAge),
+			ttl:        entry.TTL,
+			readOnly:   entry.ReadOnly,
+			size:       cache.weigh(entry.Key, entry.Value),
+		}
+		if entry.Remaining != 0 {
+			item.DeleteAt = now.Add(entry.Remaining)
+		}
+		if entry.TTL > 0 {
+			entryTTLs = true
+		}
+		if previous, exists := safeMap[entry.Key]; exists {
+			order.Remove(previous.element)
+		}
+		item.element = order.PushFront(entry.Key)
+		safeMap[entry.Key] = item
+	}
+
+	cache.init()
+	if entryTTLs {
+		cache.entryTTLs.Store(true)
+		cache.startSweeper()
+	}
+	defer cache.notifyRemoved()
+	var evicted []Entry[K, V]
+	cache.swap(safeMap, order, func(previous map[K]*StorageItem[V]) {
+		evicted = cache.retireAll(previous, safeMap)
+	})
+	cache.notifyEvicted(evicted, Cleared)
+	return nil
+}
@@ -0,0 +1,105 @@
+package lru
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/gob"
+	"errors"
+	"fmt"
+)
+
+// ErrSnapshotKeyRevoked should be returned by a SnapshotKeyResolver for keys
+// that have been deliberately destroyed. Entries sealed with such a key are
+// skipped on LoadFrom instead of failing the whole restore.
+var ErrSnapshotKeyRevoked = errors.New("snapshot encryption key revoked")
+
+// SnapshotKeyResolver selects the encryption key for each snapshotted entry,
+// typically one per tenant. Rotating or revoking a tenant's key makes their
+// persisted entries unreadable.
+type SnapshotKeyResolver interface {
+	// KeyFor returns the ID and AES-128/192/256 key used to seal the entry
+	// stored under key.
+	KeyFor(key string) (keyID string, secret []byte, err error)
+	// Key returns the AES key with the given ID.
+	Key(keyID string) (secret []byte, err error)
+}
+
+func newAEAD(secret []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealedEntry is what an encrypted snapshot entry holds: the cache key is
+// sealed along with the value, as keys are often personal data too.
+type sealedEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// sealSnapshotEntry replaces the entry key and value with their encrypted
+// encoding. The key ID is bound as additional data.
+func (cache *LRUCache[K, V]) sealSnapshotEntry(entry *snapshotEntry[K, V]) error {
+	keyID, secret, err := cache.Config.SnapshotKeys.KeyFor(keyString(entry.Key))
+	if err != nil {
+		return fmt.Errorf("resolving snapshot key for %q: %w", cache.logKey(entry.Key), err)
+	}
+	aead, err := newAEAD(secret)
+	if err != nil {
+		return fmt.Errorf("snapshot key %q: %w", keyID, err)
+	}
+	var plain bytes.Buffer
+	if err := gob.NewEncoder(&plain).Encode(sealedEntry[K, V]{Key: entry.Key, Value: entry.Value}); err != nil {
+		return fmt.Errorf("encoding snapshot entry %q: %w", cache.logKey(entry.Key), err)
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+plain.Len()+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	*entry = snapshotEntry[K, V]{
+		InsertedAge: entry.InsertedAge,
+		WrittenAge:  entry.WrittenAge,
+		AccessedAge: entry.AccessedAge,
+		Remaining:   entry.Remaining,
+		TTL:         entry.TTL,
+		ReadOnly:    entry.ReadOnly,
+		KeyID:       keyID,
+		Sealed:      aead.Seal(nonce, nonce, plain.Bytes(), []byte(keyID)),
+	}
+	return nil
+}
+
+// openSnapshotEntry restores the key and value of an entry sealed by
+// sealSnapshotEntry. Until then the key is unknown, so errors name the key
+// ID instead.
+func openSnapshotEntry[K comparable, V any](resolver SnapshotKeyResolver, entry *snapshotEntry[K, V]) error {
+	if resolver == nil {
+		return errors.New("entry is encrypted but no SnapshotKeys resolver is configured")
+	}
+	secret, err := resolver.Key(entry.KeyID)
+	if err != nil {
+		return fmt.Errorf("resolving snapshot key %q: %w", entry.KeyID, err)
+	}
+	aead, err := newAEAD(secret)
+	if err != nil {
+		return fmt.Errorf("snapshot key %q: %w", entry.KeyID, err)
+	}
+	if len(entry.Sealed) < aead.NonceSize() {
+		return fmt.Errorf("entry sealed with %q too short", entry.KeyID)
+	}
+	nonce, sealed := entry.Sealed[:aead.NonceSize()], entry.Sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, sealed, []byte(entry.KeyID))
+	if err != nil {
+		return fmt.Errorf("decrypting entry sealed with %q: %w", entry.KeyID, err)
+	}
+	var opened sealedEntry[K, V]
+	if err := gob.NewDecoder(bytes.NewReader(plain)).Decode(&opened); err != nil {
+		return fmt.Errorf("decoding entry sealed with %q: %w", entry.KeyID, err)
+	}
+	entry.Key, entry.Value = opened.Key, opened.Value
+	return nil
+}
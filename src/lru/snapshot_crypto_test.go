@@ -0,0 +1,94 @@
+package lru
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantKeys map[string][]byte
+
+func (keys tenantKeys) KeyFor(key string) (string, []byte, error) {
+	tenant, _, _ := strings.Cut(key, ":")
+	secret, err := keys.Key(tenant)
+	return tenant, secret, err
+}
+
+func (keys tenantKeys) Key(keyID string) ([]byte, error) {
+	secret, ok := keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("tenant %s: %w", keyID, ErrSnapshotKeyRevoked)
+	}
+	return secret, nil
+}
+
+func TestLRUCacheSnapshotEncryption(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	newKeys := func() tenantKeys {
+		return tenantKeys{
+			"acme":   bytes.Repeat([]byte{1}, 32),
+			"globex": bytes.Repeat([]byte{2}, 32),
+		}
+	}
+	newCache := func(keys SnapshotKeyResolver) *InMemoryLRUCache[UserData] {
+		return cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, SnapshotKeys: keys}).(*InMemoryLRUCache[UserData])
+	}
+	save := func(t *testing.T, keys SnapshotKeyResolver) *bytes.Buffer {
+		original := newCache(keys)
+		original.Set("acme:user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		original.Set("globex:user2", UserData{ID: 2, Name: "Bob", Age: 25})
+		var buf bytes.Buffer
+		assert.NoError(t, original.SaveTo(&buf))
+		return &buf
+	}
+
+	t.Run("encrypts values and restores them with the same keys", func(t *testing.T) {
+		keys := newKeys()
+		buf := save(t, keys)
+		assert.NotContains(t, buf.String(), "Alice", "Values should not be stored in the clear")
+		assert.NotContains(t, buf.String(), "user1", "Keys should not be stored in the clear")
+
+		restored := newCache(keys)
+		assert.NoError(t, restored.LoadFrom(buf))
+		value, err := restored.Get("acme:user1")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value)
+		value, err = restored.Get("globex:user2")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 2, Name: "Bob", Age: 25}, value)
+	})
+
+	t.Run("skips entries of tenants whose key was revoked", func(t *testing.T) {
+		keys := newKeys()
+		buf := save(t, keys)
+		delete(keys, "acme")
+
+		restored := newCache(keys)
+		assert.NoError(t, restored.LoadFrom(buf))
+		assert.False(t, restored.Has("acme:user1"), "Shredded tenant data should not be restored")
+		assert.True(t, restored.Has("globex:user2"))
+	})
+
+	t.Run("fails on a wrong key or a missing resolver", func(t *testing.T) {
+		keys := newKeys()
+		buf := save(t, keys)
+		snapshot := buf.Bytes()
+
+		assert.Error(t, newCache(nil).LoadFrom(bytes.NewReader(snapshot)))
+
+		keys["globex"] = bytes.Repeat([]byte{3}, 32)
+		assert.Error(t, newCache(keys).LoadFrom(bytes.NewReader(snapshot)))
+	})
+
+	t.Run("redacts keys in errors", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, SnapshotKeys: newKeys(), RedactKeys: true})
+		lruCache.Set("initech:alice@example.com", UserData{ID: 1, Name: "Alice", Age: 30})
+		err := lruCache.(*InMemoryLRUCache[UserData]).SaveTo(new(bytes.Buffer))
+		assert.ErrorIs(t, err, ErrSnapshotKeyRevoked)
+		assert.NotContains(t, err.Error(), "alice@example.com")
+		assert.Contains(t, err.Error(), redactKey("initech:alice@example.com"))
+	})
+}
@@ -0,0 +1,169 @@
+package lru
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheSnapshot(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	newCache := func(config LRUCacheConfig) *InMemoryLRUCache[UserData] {
+		return cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+	}
+
+	t.Run("round-trips values", func(t *testing.T) {
+		original := newCache(LRUCacheConfig{ItemLimit: 10, TTL: 5000})
+		original.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		original.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+
+		var buf bytes.Buffer
+		assert.NoError(t, original.SaveTo(&buf))
+		restored := newCache(LRUCacheConfig{ItemLimit: 10, TTL: 5000})
+		restored.Set("stale", UserData{ID: 9})
+		assert.NoError(t, restored.LoadFrom(&buf))
+
+		assert.False(t, restored.Has("stale"), "LoadFrom should replace existing contents")
+		value, err := restored.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value)
+		value, err = restored.Get("user2")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 2, Name: "Bob", Age: 25}, value)
+	})
+
+	t.Run("preserves recency order", func(t *testing.T) {
+		original := newCache(LRUCacheConfig{ItemLimit: 3})
+		original.Set("user1", UserData{ID: 1})
+		original.Set("user2", UserData{ID: 2})
+		original.Set("user3", UserData{ID: 3})
+		original.Get("user1")
+
+		var buf bytes.Buffer
+		assert.NoError(t, original.SaveTo(&buf))
+		restored := newCache(LRUCacheConfig{ItemLimit: 3})
+		assert.NoError(t, restored.LoadFrom(&buf))
+
+		restored.Set("user4", UserData{ID: 4})
+		assert.False(t, restored.Has("user2"), "Least recently used key 'user2' should be evicted first")
+		restored.Set("user5", UserData{ID: 5})
+		assert.False(t, restored.Has("user3"))
+		assert.True(t, restored.Has("user1"))
+	})
+
+	t.Run("keeps only the most recent entries when over capacity", func(t *testing.T) {
+		original := newCache(LRUCacheConfig{ItemLimit: 3})
+		original.Set("user1", UserData{ID: 1})
+		original.Set("user2", UserData{ID: 2})
+		original.Set("user3", UserData{ID: 3})
+
+		var buf bytes.Buffer
+		assert.NoError(t, original.SaveTo(&buf))
+		restored := newCache(LRUCacheConfig{ItemLimit: 2})
+		assert.NoError(t, restored.LoadFrom(&buf))
+
+		assert.False(t, restored.Has("user1"))
+		assert.True(t, restored.Has("user2"))
+		assert.True(t, restored.Has("user3"))
+	})
+
+	t.Run("preserves remaining TTL", func(t *testing.T) {
+		original := newCache(LRUCacheConfig{ItemLimit: 10, TTL: 300, TTLMode: AbsoluteTTL})
+		original.Set("user1", UserData{ID: 1})
+		time.Sleep(150 * time.Millisecond)
+
+		var buf bytes.Buffer
+		assert.NoError(t, original.SaveTo(&buf))
+		restored := newCache(LRUCacheConfig{ItemLimit: 10, TTL: 300, TTLMode: AbsoluteTTL})
+		assert.NoError(t, restored.LoadFrom(&buf))
+
+		assert.True(t, restored.Has("user1"))
+		time.Sleep(250 * time.Millisecond)
+		assert.False(t, restored.Has("user1"), "Restored key should expire at its original deadline")
+	})
+
+	t.Run("skips expired entries", func(t *testing.T) {
+		original := newCache(LRUCacheConfig{ItemLimit: 10, TTL: 5000, IdleTTL: 50})
+		original.Set("user1", UserData{ID: 1})
+		original.Storage.mu.Lock()
+		original.Storage.SafeMap["user1"].DeleteAt = time.Now().Add(-time.Millisecond)
+		original.Storage.mu.Unlock()
+
+		var buf bytes.Buffer
+		assert.NoError(t, original.SaveTo(&buf))
+		restored := newCache(LRUCacheConfig{ItemLimit: 10, TTL: 5000})
+		assert.NoError(t, restored.LoadFrom(&buf))
+		assert.False(t, restored.Has("user1"))
+	})
+
+	t.Run("partial snapshot keeps the hottest entries", func(t *testing.T) {
+		original := newCache(LRUCacheConfig{ItemLimit: 10})
+		original.Set("user1", UserData{ID: 1})
+		original.Set("user2", UserData{ID: 2})
+		original.Set("user3", UserData{ID: 3})
+		original.Get("user1")
+
+		var buf bytes.Buffer
+		assert.NoError(t, original.SaveHottestTo(&buf, 2))
+		restored := newCache(LRUCacheConfig{ItemLimit: 2})
+		assert.NoError(t, restored.LoadFrom(&buf))
+
+		assert.ElementsMatch(t, []string{"user1", "user3"}, slices.Collect(restored.KeysSeq()), "Coldest key 'user2' should not be in the partial snapshot")
+
+		restored.Set("user4", UserData{ID: 4})
+		assert.False(t, restored.Has("user3"), "Recency order should carry over for the saved entries")
+		assert.True(t, restored.Has("user1"))
+		assert.Error(t, original.SaveHottestTo(&buf, 0))
+	})
+
+	t.Run("writers are not blocked while the snapshot is written", func(t *testing.T) {
+		original := newCache(LRUCacheConfig{ItemLimit: 10})
+		original.Set("user1", UserData{ID: 1})
+
+		reader, writer := io.Pipe()
+		saved := make(chan error)
+		go func() { saved <- original.SaveTo(writer) }()
+
+		set := make(chan struct{})
+		go func() {
+			original.Set("user2", UserData{ID: 2})
+			original.Get("user1")
+			close(set)
+		}()
+		select {
+		case <-set:
+		case <-time.After(time.Second):
+			t.Fatal("Set blocked behind an unread snapshot")
+		}
+
+		restored := newCache(LRUCacheConfig{ItemLimit: 10})
+		assert.NoError(t, restored.LoadFrom(reader))
+		assert.NoError(t, <-saved)
+		assert.True(t, restored.Has("user1"))
+	})
+
+	t.Run("rejects garbage input", func(t *testing.T) {
+		restored := newCache(LRUCacheConfig{ItemLimit: 10})
+		assert.Error(t, restored.LoadFrom(bytes.NewBufferString("not a snapshot")))
+	})
+
+	t.Run("rejects corrupted entry counts", func(t *testing.T) {
+		original := newCache(LRUCacheConfig{ItemLimit: 10})
+		original.Set("user1", UserData{ID: 1})
+		restored := newCache(LRUCacheConfig{ItemLimit: 10})
+		for _, count := range []int{-1, math.MaxInt} {
+			var buf bytes.Buffer
+			encoder := gob.NewEncoder(&buf)
+			assert.NoError(t, encoder.Encode(snapshotHeader{Version: snapshotVersion, Count: count}))
+			assert.NoError(t, encoder.Encode(snapshotEntry[string, UserData]{Key: "user2", Value: UserData{ID: 2}}))
+			assert.Error(t, restored.LoadFrom(&buf))
+		}
+		assert.Equal(t, 0, restored.Len())
+	})
+}
@@ -0,0 +1,294 @@
+package lru
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type WindowStats struct {
+	HitsPerSecond   float64
+	MissesPerSecond float64
+	// MissRatio is misses/(hits+misses) within the window, zero without traffic.
+	MissRatio float64
+	// for adding up windows of different lengths, see addWindowStats
+	hits, misses uint64
+}
+
+type Stats struct {
+	Hits uint64
+	// FreshHits, StaleHits and ErrorFallbacks break Hits down by how
+	// current the returned value was. StaleHits are entries due for
+	// Hooks.Revalidate that were served while another Get was checking
+	// them; ErrorFallbacks are entries served because checking them failed.
+	// Without RevalidateAfter, every hit is fresh.
+	FreshHits      uint64
+	StaleHits      uint64
+	ErrorFallbacks uint64
+	Misses         uint64
+	// Sets counts writes made by callers through Set.
+	Sets uint64
+	// Fills counts writes made on the cache's behalf, such as tiered
+	// promotions and other SetWithProvenance calls.
+	Fills     uint64
+	Evictions uint64
+	// PrematureEvictions counts evicted entries read back from the victim
+	// cache, see Config.VictimCacheSize. Many of them compared to Evictions
+	// suggest ItemLimit is too small.
+	PrematureEvictions uint64
+	// EvictionVetoes counts candidates kept by Hooks.OnBeforeEvict.
+	EvictionVetoes uint64
+	Expirations    uint64
+	// Deletions, Replacements and Cleared count the values removed for the
+	// other eviction reasons: by Delete and DeletePrefix, by being
+	// overwritten while still live, and by Clear. SwapAll and LoadFrom count
+	// the values they overwrite as replaced and the others as cleared.
+	Deletions    uint64
+	Replacements uint64
+	Cleared      uint64
+	// Rejections counts values refused by Hooks.Validate, and
+	// ReadOnlyRejections writes refused because the entry was read-only, see
+	// SetReadOnly and Config.WriteOnce.
+	Rejections         uint64
+	ReadOnlyRejections uint64
+	// CallbackPanics and SlowCallbacks count hooks that panicked or timed
+	// out, see Config.RecoverCallbackPanics and Config.CallbackTimeout.
+	CallbackPanics uint64
+	SlowCallbacks  uint64
+	// ExpiryPaused is set between PauseExpiry and ResumeExpiry.
+	ExpiryPaused bool
+
+	LastMinute         WindowStats
+	LastFiveMinutes    WindowStats
+	LastFifteenMinutes WindowStats
+}
+
+const (
+	statsSampleInterval = time.Second
+	statsHistory        = 15 * time.Minute
+)
+
+type statsSample struct {
+	at     time.Time
+	hits   uint64
+	misses uint64
+}
+
+// cacheStats keeps lock-free counters. Rolling windows are derived from
+// samples taken once per statsSampleInterval by a goroutine started on the
+// first Stats call, so caches nobody inspects pay nothing for them.
+type cacheStats struct {
+	hits               atomic.Uint64
+	staleHits          atomic.Uint64
+	errorFallbacks     atomic.Uint64
+	misses             atomic.Uint64
+	sets               atomic.Uint64
+	fills              atomic.Uint64
+	evictions          atomic.Uint64
+	prematureEvictions atomic.Uint64
+	evictionVetoes     atomic.Uint64
+	expirations        atomic.Uint64
+	deletions          atomic.Uint64
+	replacements       atomic.Uint64
+	cleared            atomic.Uint64
+	rejections         atomic.Uint64
+	readOnlyRejections atomic.Uint64
+	callbackPanics     atomic.Uint64
+	slowCallbacks      atomic.Uint64
+
+	createdAt time.Time
+	// see LRUCacheConfig.Background
+	background  *Background
+	samplerOnce sync.Once
+	// set when the sampler starts, stopBackground if it runs on background
+	samplerStop    chan struct{}
+	samplerExited  chan struct{}
+	stopBackground func(wait bool)
+	mu             sync.Mutex
+	samples        []statsSample
+	// counters as of the previous StatsDelta call
+	deltaMu   sync.Mutex
+	lastDelta Stats
+}
+
+func (stats *cacheStats) current(now time.Time) statsSample {
+	return statsSample{at: now, hits: stats.hits.Load(), misses: stats.misses.Load()}
+}
+
+func (stats *cacheStats) startSampler() {
+	stats.samplerOnce.Do(func() {
+		if !stats.createdAt.IsZero() {
+			// counters start at zero, so windows can cover traffic from
+			// before the first Stats call
+			stats.addSample(statsSample{at: stats.createdAt})
+		}
+		stats.addSample(stats.current(time.Now()))
+		if stats.background != nil {
+			stats.stopBackground = stats.background.every(statsSampleInterval, func() {
+				stats.addSample(stats.current(time.Now()))
+			})
+			return
+		}
+		stats.samplerStop = make(chan struct{})
+		stats.samplerExited = make(chan struct{})
+		go func() {
+			defer close(stats.samplerExited)
+			ticker := time.NewTicker(statsSampleInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					stats.addSample(stats.current(time.Now()))
+				case <-stats.samplerStop:
+					return
+				}
+			}
+		}()
+	})
+}
+
+// close stops the sampler, or keeps it from starting. Must only be called
+// once.
+func (stats *cacheStats) close() {
+	stats.samplerOnce.Do(func() {})
+	if stats.samplerStop != nil {
+		close(stats.samplerStop)
+		<-stats.samplerExited
+	}
+	if stats.stopBackground != nil {
+		stats.stopBackground(true)
+	}
+}
+
+func (stats *cacheStats) addSample(sample statsSample) {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	stats.samples = append(stats.samples, sample)
+	if limit := int(statsHistory/statsSampleInterval) + 1; len(stats.samples) > limit {
+		stats.samples = append(stats.samples[:0], stats.samples[len(stats.samples)-limit:]...)
+	}
+}
+
+// window compares latest against the oldest sample no older than d. With
+// less history than d, the rate covers the history that is available.
+func (stats *cacheStats) window(latest statsSample, d time.Duration) WindowStats {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	var base *statsSample
+	for i := range stats.samples {
+		if !stats.samples[i].at.Before(latest.at.Add(-d)) {
+			base = &stats.samples[i]
+			break
+		}
+	}
+	if base == nil {
+		return WindowStats{}
+	}
+	elapsed := latest.at.Sub(base.at).Seconds()
+	hits := float64(latest.hits - base.hits)
+	misses := float64(latest.misses - base.misses)
+	window := WindowStats{hits: latest.hits - base.hits, misses: latest.misses - base.misses}
+	if elapsed > 0 {
+		window.HitsPerSecond = hits / elapsed
+		window.MissesPerSecond = misses / elapsed
+	}
+	if hits+misses > 0 {
+		window.MissRatio = misses / (hits + misses)
+	}
+	return window
+}
+
+func (stats *cacheStats) snapshot() Stats {
+	stats.startSampler()
+	// loaded before hits, which are counted first, so FreshHits can't wrap
+	staleHits, errorFallbacks := stats.staleHits.Load(), stats.errorFallbacks.Load()
+	latest := stats.current(time.Now())
+	return Stats{
+		Hits:               latest.hits,
+		FreshHits:          latest.hits - staleHits - errorFallbacks,
+		StaleHits:          staleHits,
+		ErrorFallbacks:     errorFallbacks,
+		Misses:             latest.misses,
+		Sets:               stats.sets.Load(),
+		Fills:              stats.fills.Load(),
+		Evictions:          stats.evictions.Load(),
+		PrematureEvictions: stats.prematureEvictions.Load(),
+		EvictionVetoes:     stats.evictionVetoes.Load(),
+		Expirations:        stats.expirations.Load(),
+		Deletions:          stats.deletions.Load(),
+		Replacements:       stats.replacements.Load(),
+		Cleared:            stats.cleared.Load(),
+		Rejections:         stats.rejections.Load(),
+		ReadOnlyRejections: stats.readOnlyRejections.Load(),
+		CallbackPanics:     stats.callbackPanics.Load(),
+		SlowCallbacks:      stats.slowCallbacks.Load(),
+		LastMinute:         stats.window(latest, time.Minute),
+		LastFiveMinutes:    stats.window(latest, 5*time.Minute),
+		LastFifteenMinutes: stats.window(latest, 15*time.Minute),
+	}
+}
+
+func (stats *cacheStats) delta() Stats {
+	stats.deltaMu.Lock()
+	defer stats.deltaMu.Unlock()
+	current := stats.snapshot()
+	delta := current
+	delta.Hits -= stats.lastDelta.Hits
+	delta.FreshHits -= stats.lastDelta.FreshHits
+	delta.StaleHits -= stats.lastDelta.StaleHits
+	delta.ErrorFallbacks -= stats.lastDelta.ErrorFallbacks
+	delta.Misses -= stats.lastDelta.Misses
+	delta.Sets -= stats.lastDelta.Sets
+	delta.Fills -= stats.lastDelta.Fills
+	delta.Evictions -= stats.lastDelta.Evictions
+	delta.PrematureEvictions -= stats.lastDelta.PrematureEvictions
+	delta.EvictionVetoes -= stats.lastDelta.EvictionVetoes
+	delta.Expirations -= stats.lastDelta.Expirations
+	delta.Deletions -= stats.lastDelta.Deletions
+	delta.Replacements -= stats.lastDelta.Replacements
+	delta.Cleared -= stats.lastDelta.Cleared
+	delta.Rejections -= stats.lastDelta.Rejections
+	delta.ReadOnlyRejections -= stats.lastDelta.ReadOnlyRejections
+	delta.CallbackPanics -= stats.lastDelta.CallbackPanics
+	delta.SlowCallbacks -= stats.lastDelta.SlowCallbacks
+	stats.lastDelta = current
+	return delta
+}
+
+func (cache *LRUCache[K, V]) Stats() Stats {
+	cache.init()
+	stats := cache.stats.snapshot()
+	stats.ExpiryPaused = cache.expiryPaused.Load()
+	return stats
+}
+
+// StatsDelta is like Stats, but the counters only cover what happened since
+// the previous StatsDelta call. Rolling windows are reported as usual.
+func (cache *LRUCache[K, V]) StatsDelta() Stats {
+	cache.init()
+	stats := cache.stats.delta()
+	stats.ExpiryPaused = cache.expiryPaused.Load()
+	return stats
+}
+
+// OnStats calls fn with a Stats snapshot every interval until stop is called.
+func (cache *LRUCache[K, V]) OnStats(interval time.Duration, fn func(Stats)) (stop func()) {
+	if background := cache.Config.Background; background != nil {
+		return background.Every(interval, func() { fn(cache.Stats()) })
+	}
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fn(cache.Stats())
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
@@ -0,0 +1,152 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheStats(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+	t.Run("counts hits, misses, sets, evictions and expirations", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 1, TTL: 100}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Get("user1")
+		lruCache.Get("user2")
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+
+		time.Sleep(200 * time.Millisecond)
+		stats := lruCache.Stats()
+		assert.Equal(t, uint64(1), stats.Hits)
+		assert.Equal(t, uint64(1), stats.Misses)
+		assert.Equal(t, uint64(2), stats.Sets)
+		assert.Equal(t, uint64(1), stats.Evictions)
+		assert.Equal(t, uint64(1), stats.Expirations)
+	})
+
+	t.Run("counts deletions, replacements and cleared entries", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 31})
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+		lruCache.Delete("user2")
+		lruCache.Delete("user2")
+		lruCache.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+		lruCache.Set("user3", UserData{ID: 3, Name: "Carol", Age: 41})
+		lruCache.Clear()
+
+		stats := lruCache.Stats()
+		assert.Equal(t, uint64(1), stats.Replacements)
+		assert.Equal(t, uint64(1), stats.Deletions)
+		assert.Equal(t, uint64(3), stats.Cleared)
+	})
+
+	t.Run("tiered promotions count as fills, not sets", func(t *testing.T) {
+		upper := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lower := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		lruCache := &TieredLRUCache[UserData]{Tiers: []LRUCacher[UserData]{upper, lower}}
+		lower.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Get("user1")
+		upper.Set("user2", UserData{ID: 2, Name: "Bob", Age: 25})
+
+		stats := upper.Stats()
+		assert.Equal(t, uint64(1), stats.Sets)
+		assert.Equal(t, uint64(1), stats.Fills)
+	})
+
+	t.Run("frozen reads are counted", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Freeze()
+		lruCache.Get("user1")
+		lruCache.Get("user2")
+
+		stats := lruCache.Stats()
+		assert.Equal(t, uint64(1), stats.Hits)
+		assert.Equal(t, uint64(1), stats.Misses)
+	})
+
+	t.Run("StatsDelta reports changes since the previous call", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Get("user1")
+		lruCache.Get("user1")
+
+		delta := lruCache.StatsDelta()
+		assert.Equal(t, uint64(2), delta.Hits)
+		assert.Equal(t, uint64(1), delta.Sets)
+
+		lruCache.Get("user1")
+		lruCache.Get("user2")
+		delta = lruCache.StatsDelta()
+		assert.Equal(t, uint64(1), delta.Hits)
+		assert.Equal(t, uint64(1), delta.Misses)
+		assert.Equal(t, uint64(0), delta.Sets)
+		assert.Equal(t, uint64(3), lruCache.Stats().Hits, "Stats should stay cumulative")
+	})
+
+	t.Run("OnStats delivers periodic snapshots until stopped", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+
+		snapshots := make(chan Stats, 10)
+		stop := lruCache.OnStats(20*time.Millisecond, func(stats Stats) { snapshots <- stats })
+		select {
+		case stats := <-snapshots:
+			assert.Equal(t, uint64(1), stats.Sets)
+		case <-time.After(time.Second):
+			t.Fatal("OnStats callback was not called")
+		}
+
+		stop()
+		stop()
+		time.Sleep(50 * time.Millisecond)
+		for len(snapshots) > 0 {
+			<-snapshots
+		}
+		time.Sleep(50 * time.Millisecond)
+		assert.Empty(t, snapshots, "No snapshots should be delivered after stop")
+	})
+
+	t.Run("rolling windows are computed from samples", func(t *testing.T) {
+		var stats cacheStats
+		start := time.Now().Add(-20 * time.Minute)
+		for i := 0; i <= 20; i++ {
+			// 10 hits/s and 10 misses/s for the first 10 minutes, then 30 hits/s
+			sample := statsSample{at: start.Add(time.Duration(i) * time.Minute)}
+			if i <= 10 {
+				sample.hits, sample.misses = uint64(i*600), uint64(i*600)
+			} else {
+				sample.hits, sample.misses = 6000+uint64(i-10)*1800, 6000
+			}
+			stats.addSample(sample)
+		}
+		latest := stats.samples[len(stats.samples)-1]
+
+		lastMinute := stats.window(latest, time.Minute)
+		assert.InDelta(t, 30, lastMinute.HitsPerSecond, 0.001)
+		assert.InDelta(t, 0, lastMinute.MissRatio, 0.001)
+
+		lastFifteen := stats.window(latest, 15*time.Minute)
+		assert.InDelta(t, (5*600+10*1800)/900.0, lastFifteen.HitsPerSecond, 0.001)
+		assert.InDelta(t, 5*600/900.0, lastFifteen.MissesPerSecond, 0.001)
+		assert.InDelta(t, 3000/24000.0, lastFifteen.MissRatio, 0.001)
+	})
+
+	t.Run("windows cover the available history", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		for i := 0; i < 3; i++ {
+			lruCache.Get("user1")
+		}
+		lruCache.Get("user2")
+		time.Sleep(50 * time.Millisecond)
+
+		stats := lruCache.Stats()
+		assert.Greater(t, stats.LastMinute.HitsPerSecond, 0.0)
+		assert.InDelta(t, 0.25, stats.LastMinute.MissRatio, 0.001)
+		assert.Equal(t, stats.LastMinute, stats.LastFifteenMinutes)
+	})
+}
@@ -0,0 +1,208 @@
+package lru
+
+import (
+	"context"
+	"time"
+)
+
+// HealthChecker can be implemented by a tier to report whether it is able to
+// serve traffic. Tiers that don't implement it are always considered healthy.
+type HealthChecker interface {
+	Healthy() bool
+}
+
+type TieredLRUCacheProvider[T any] struct {
+	Providers  []LRUCacheProvider[T]
+	Node       string
+	Loader     func(ctx context.Context, key string) (T, error)
+	HedgeDelay time.Duration
+}
+
+func (cacheProvider TieredLRUCacheProvider[T]) NewLRUCache(config LRUCacheConfig) LRUCacher[T] {
+	tiers := make([]LRUCacher[T], 0, len(cacheProvider.Providers))
+	for _, provider := range cacheProvider.Providers {
+		tiers = append(tiers, provider.NewLRUCache(config))
+	}
+	return &TieredLRUCache[T]{
+		Tiers:      tiers,
+		Node:       cacheProvider.Node,
+		Loader:     cacheProvider.Loader,
+		HedgeDelay: cacheProvider.HedgeDelay,
+	}
+}
+
+// TieredLRUCache chains several caches in priority order (e.g. memory ->
+// redis -> loader). Unhealthy tiers are skipped and rejoin as soon as they
+// report healthy again; hits on a lower tier are promoted to the healthy
+// tiers above it, recording the source tier and Node as their Provenance.
+type TieredLRUCache[T any] struct {
+	Tiers []LRUCacher[T]
+	// Node identifies this process in the Provenance of promoted entries.
+	Node string
+	// Loader is called when no tier has the key. Its result is written to
+	// every healthy tier.
+	Loader func(ctx context.Context, key string) (T, error)
+	// HedgeDelay hedges slow reads: when the tiers below the first one haven't
+	// answered within it, Loader is started in parallel and the first
+	// success wins; the loser's context is cancelled. Zero only calls Loader
+	// once every tier has missed.
+	HedgeDelay time.Duration
+}
+
+type tierRead[T any] struct {
+	value T
+	// index into Tiers, -1 for loaded values
+	tier int
+	took time.Duration
+	err  error
+}
+
+func healthy[T any](tier LRUCacher[T]) bool {
+	checker, ok := tier.(HealthChecker)
+	return !ok || checker.Healthy()
+}
+
+func (cache *TieredLRUCache[T]) healthyTiers() []LRUCacher[T] {
+	tiers := make([]LRUCacher[T], 0, len(cache.Tiers))
+	for _, tier := range cache.Tiers {
+		if healthy(tier) {
+			tiers = append(tiers, tier)
+		}
+	}
+	return tiers
+}
+
+func (cache *TieredLRUCache[T]) healthyIndexes() []int {
+	indexes := make([]int, 0, len(cache.Tiers))
+	for i, tier := range cache.Tiers {
+		if healthy(tier) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (cache *TieredLRUCache[T]) Has(key string) bool {
+	for _, tier := range cache.healthyTiers() {
+		if tier.Has(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (cache *TieredLRUCache[T]) Get(key string) (T, error) {
+	return cache.GetContext(context.Background(), key)
+}
+
+// GetContext is like Get; ctx is passed on to Loader.
+func (cache *TieredLRUCache[T]) GetContext(ctx context.Context, key string) (T, error) {
+	indexes := cache.healthyIndexes()
+	if cache.Loader == nil || cache.HedgeDelay <= 0 || len(indexes) < 2 {
+		read := cache.readTiers(key, indexes)
+		if read.err != nil && cache.Loader != nil {
+			read = cache.load(ctx, key)
+		}
+		return cache.finish(key, indexes, read)
+	}
+
+	if read := cache.readTiers(key, indexes[:1]); read.err == nil {
+		return read.value, nil
+	}
+	remote := make(chan tierRead[T], 1)
+	go func() { remote <- cache.readTiers(key, indexes[1:]) }()
+	timer := time.NewTimer(cache.HedgeDelay)
+	defer timer.Stop()
+	select {
+	case read := <-remote:
+		if read.err != nil {
+			read = cache.load(ctx, key)
+		}
+		return cache.finish(key, indexes, read)
+	case <-timer.C:
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+
+	loadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	loaded := make(chan tierRead[T], 1)
+	go func() { loaded <- cache.load(loadCtx, key) }()
+	var read tierRead[T]
+	for pending := 2; pending > 0; pending-- {
+		select {
+		case read = <-remote:
+		case read = <-loaded:
+		}
+		if read.err == nil {
+			break
+		}
+	}
+	return cache.finish(key, indexes, read)
+}
+
+// readTiers returns the value from the first of the given tiers that has key.
+func (cache *TieredLRUCache[T]) readTiers(key string, indexes []int) tierRead[T] {
+	for _, i := range indexes {
+		start := time.Now()
+		value, err := cache.Tiers[i].Get(key)
+		if err == nil {
+			return tierRead[T]{value: value, tier: i, took: time.Since(start)}
+		}
+	}
+	return tierRead[T]{tier: -1, err: ErrKeyNotFound}
+}
+
+func (cache *TieredLRUCache[T]) load(ctx context.Context, key string) tierRead[T] {
+	start := time.Now()
+	value, err := cache.Loader(ctx, key)
+	return tierRead[T]{value: value, tier: -1, took: time.Since(start), err: err}
+}
+
+// finish writes a successful read to the healthy tiers above the one it
+// came from, or to all of them for loaded values.
+func (cache *TieredLRUCache[T]) finish(key string, indexes []int, read tierRead[T]) (T, error) {
+	if read.err != nil {
+		var zero T
+		return zero, read.err
+	}
+	provenance := Provenance{SourceTier: read.tier + 1, Node: cache.Node, LoadDuration: read.took}
+	for _, i := range indexes {
+		if i == read.tier {
+			break
+		}
+		if writer, ok := cache.Tiers[i].(provenanceWriter[T]); ok {
+			writer.SetWithProvenance(key, read.value, provenance)
+		} else {
+			cache.Tiers[i].Set(key, read.value)
+		}
+	}
+	return read.value, nil
+}
+
+// Delete removes key from every tier, unhealthy ones included, so they
+// don't serve the old value once they recover.
+func (cache *TieredLRUCache[T]) Delete(key string) bool {
+	deleted := false
+	for _, tier := range cache.Tiers {
+		if tier.Delete(key) {
+			deleted = true
+		}
+	}
+	return deleted
+}
+
+// Clear clears every tier, unhealthy ones included.
+func (cache *TieredLRUCache[T]) Clear() {
+	for _, tier := range cache.Tiers {
+		tier.Clear()
+	}
+}
+
+func (cache *TieredLRUCache[T]) Set(key string, value T) T {
+	for _, tier := range cache.healthyTiers() {
+		tier.Set(key, value)
+	}
+	return value
+}
@@ -0,0 +1,187 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flakyTier[T any] struct {
+	LRUCacher[T]
+	healthy bool
+}
+
+func (tier *flakyTier[T]) Healthy() bool {
+	return tier.healthy
+}
+
+// slowTier answers reads after a delay, like a remote cache would.
+type slowTier[T any] struct {
+	LRUCacher[T]
+	delay time.Duration
+}
+
+func (tier *slowTier[T]) Get(key string) (T, error) {
+	time.Sleep(tier.delay)
+	return tier.LRUCacher.Get(key)
+}
+
+func TestTieredLRUCache(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+	t.Run("builds one tier per provider", func(t *testing.T) {
+		tieredProvider := TieredLRUCacheProvider[UserData]{Providers: []LRUCacheProvider[UserData]{cacheProvider, cacheProvider}}
+		lruCache := tieredProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000})
+		assert.Len(t, lruCache.(*TieredLRUCache[UserData]).Tiers, 2)
+
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value)
+	})
+
+	t.Run("promotes lower tier hits to upper tiers", func(t *testing.T) {
+		upper := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000})
+		lower := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000})
+		lruCache := &TieredLRUCache[UserData]{Tiers: []LRUCacher[UserData]{upper, lower}}
+
+		lower.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		assert.False(t, upper.Has("user1"))
+
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value)
+		assert.True(t, upper.Has("user1"), "Hit on lower tier should be promoted")
+	})
+
+	t.Run("skips unhealthy tiers and re-promotes once they recover", func(t *testing.T) {
+		upper := &flakyTier[UserData]{LRUCacher: cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000}), healthy: false}
+		lower := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000})
+		lruCache := &TieredLRUCache[UserData]{Tiers: []LRUCacher[UserData]{upper, lower}}
+
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		assert.False(t, upper.LRUCacher.Has("user1"), "Unhealthy tier should not receive writes")
+		assert.True(t, lruCache.Has("user1"))
+
+		upper.healthy = true
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value)
+		assert.True(t, upper.LRUCacher.Has("user1"), "Recovered tier should be re-promoted on read")
+	})
+
+	t.Run("deletes from every tier", func(t *testing.T) {
+		upper := &flakyTier[UserData]{LRUCacher: cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000}), healthy: true}
+		lower := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000})
+		lruCache := &TieredLRUCache[UserData]{Tiers: []LRUCacher[UserData]{upper, lower}}
+
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		upper.healthy = false
+		assert.True(t, lruCache.Delete("user1"))
+
+		upper.healthy = true
+		assert.False(t, lruCache.Has("user1"), "Recovered tier should not serve deleted keys")
+		assert.False(t, lruCache.Delete("user1"))
+	})
+
+	t.Run("clears every tier", func(t *testing.T) {
+		upper := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000})
+		lower := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000})
+		lruCache := &TieredLRUCache[UserData]{Tiers: []LRUCacher[UserData]{upper, lower}}
+
+		lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache.Clear()
+		assert.False(t, upper.Has("user1"))
+		assert.False(t, lower.Has("user1"))
+	})
+
+	t.Run("returns error when no tier has the key", func(t *testing.T) {
+		tieredProvider := TieredLRUCacheProvider[UserData]{Providers: []LRUCacheProvider[UserData]{cacheProvider}}
+		lruCache := tieredProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 1000})
+		value, err := lruCache.Get("user1")
+		assert.Error(t, err)
+		assert.Empty(t, value)
+		assert.False(t, lruCache.Has("user1"))
+	})
+
+	t.Run("loads keys no tier has and fills every tier", func(t *testing.T) {
+		upper := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		lower := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		var loads atomic.Int32
+		lruCache := &TieredLRUCache[UserData]{Tiers: []LRUCacher[UserData]{upper, lower}, Loader: func(ctx context.Context, key string) (UserData, error) {
+			loads.Add(1)
+			if key != "user1" {
+				return UserData{}, errors.New("no such user")
+			}
+			return UserData{ID: 1, Name: "Alice", Age: 30}, nil
+		}}
+
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, value)
+		assert.True(t, upper.Has("user1"))
+		assert.True(t, lower.Has("user1"))
+		lruCache.Get("user1")
+		assert.Equal(t, int32(1), loads.Load())
+
+		_, err = lruCache.Get("user2")
+		assert.EqualError(t, err, "no such user")
+	})
+
+	t.Run("hedges slow tiers with the loader", func(t *testing.T) {
+		upper := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		lower := &slowTier[UserData]{LRUCacher: cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}), delay: 300 * time.Millisecond}
+		lower.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		lruCache := &TieredLRUCache[UserData]{Tiers: []LRUCacher[UserData]{upper, lower}, HedgeDelay: 20 * time.Millisecond, Loader: func(ctx context.Context, key string) (UserData, error) {
+			return UserData{ID: 1, Name: "Alice (loaded)", Age: 30}, nil
+		}}
+
+		start := time.Now()
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Alice (loaded)", value.Name)
+		assert.Less(t, time.Since(start), 200*time.Millisecond, "Loader should answer before the slow tier")
+		assert.True(t, upper.Has("user1"))
+	})
+
+	t.Run("doesn't hedge tiers that answer in time", func(t *testing.T) {
+		upper := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		lower := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		lower.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		var loads atomic.Int32
+		lruCache := &TieredLRUCache[UserData]{Tiers: []LRUCacher[UserData]{upper, lower}, HedgeDelay: 100 * time.Millisecond, Loader: func(ctx context.Context, key string) (UserData, error) {
+			loads.Add(1)
+			return UserData{}, nil
+		}}
+
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Alice", value.Name)
+		assert.Zero(t, loads.Load())
+	})
+
+	t.Run("cancels the loader when the tier wins", func(t *testing.T) {
+		upper := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		lower := &slowTier[UserData]{LRUCacher: cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10}), delay: 50 * time.Millisecond}
+		lower.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+		cancelled := make(chan struct{})
+		lruCache := &TieredLRUCache[UserData]{Tiers: []LRUCacher[UserData]{upper, lower}, HedgeDelay: 10 * time.Millisecond, Loader: func(ctx context.Context, key string) (UserData, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return UserData{}, ctx.Err()
+		}}
+
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Alice", value.Name)
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("Loader should be cancelled")
+		}
+	})
+}
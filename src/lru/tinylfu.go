@@ -0,0 +1,214 @@
+package lru
+
+import (
+	"container/list"
+	"hash/maphash"
+	"math/bits"
+)
+
+// tinyLFU implements TinyLFUAdmission in front of main, the policy picked
+// by Config.Eviction.
+type tinyLFU[K comparable] struct {
+	windowLimit int
+	// newest first
+	window     *list.List
+	windowKeys map[K]*list.Element
+	main       evictionPolicy[K]
+	sketch     *frequencySketch[K]
+}
+
+func newTinyLFU[K comparable](itemLimit int64, main evictionPolicy[K]) *tinyLFU[K] {
+	return &tinyLFU[K]{
+		windowLimit: max(1, int(itemLimit/100)),
+		window:      list.New(),
+		windowKeys:  make(map[K]*list.Element),
+		main:        main,
+		sketch:      newFrequencySketch[K](itemLimit),
+	}
+}
+
+// added moves the oldest key of a full window to main unopposed: until the
+// cache is full there is no victim to compete with.
+func (policy *tinyLFU[K]) added(key K) {
+	policy.sketch.increment(key)
+	policy.windowKeys[key] = policy.window.PushFront(key)
+	if policy.window.Len() > policy.windowLimit {
+		oldest := policy.window.Back().Value.(K)
+		policy.removed(oldest)
+		policy.main.added(oldest)
+	}
+}
+
+func (policy *tinyLFU[K]) accessed(key K) {
+	policy.sketch.increment(key)
+	if element, exists := policy.windowKeys[key]; exists {
+		policy.window.MoveToFront(element)
+		return
+	}
+	policy.main.accessed(key)
+}
+
+func (policy *tinyLFU[K]) missed(key K) {
+	policy.sketch.increment(key)
+}
+
+func (policy *tinyLFU[K]) removed(key K) {
+	if element, exists := policy.windowKeys[key]; exists {
+		policy.window.Remove(element)
+		delete(policy.windowKeys, key)
+		return
+	}
+	policy.main.removed(key)
+}
+
+func (policy *tinyLFU[K]) renamed(oldKey, newKey K) {
+	if element, exists := policy.windowKeys[oldKey]; exists {
+		element.Value = newKey
+		policy.windowKeys[newKey] = element
+		delete(policy.windowKeys, oldKey)
+		return
+	}
+	policy.main.renamed(oldKey, newKey)
+}
+
+// victim is called right before a new key is added, so the window is
+// drained once it is full. Its oldest key then either replaces the main
+// policy's victim or is evicted itself. When the victim picked that way is
+// skipped, the oldest window key that isn't is evicted instead, and then
+// the main policy's.
+func (policy *tinyLFU[K]) victim(skip func(key K) bool) (K, bool) {
+	triedMain := false
+	if policy.window.Len() < policy.windowLimit {
+		if key, exists := policy.main.victim(skip); exists {
+			return key, true
+		}
+		triedMain = true
+	}
+	oldest := policy.window.Back()
+	if oldest == nil {
+		var zero K
+		return zero, false
+	}
+	if candidate := oldest.Value.(K); !triedMain && policy.admits(candidate) {
+		triedMain = true
+		if key, exists := policy.main.victim(skip); exists {
+			policy.removed(candidate)
+			policy.main.added(candidate)
+			return key, true
+		}
+	}
+	if element := oldestUnskipped(policy.window, skip); element != nil {
+		key := element.Value.(K)
+		policy.removed(key)
+		return key, true
+	}
+	if !triedMain {
+		return policy.main.victim(skip)
+	}
+	var zero K
+	return zero, false
+}
+
+// admits reports whether candidate, on its way out of the window, was
+// requested more often than the main policy's next victim.
+func (policy *tinyLFU[K]) admits(candidate K) bool {
+	victims := policy.main.victims(1)
+	return len(victims) > 0 && policy.sketch.estimate(candidate) > policy.sketch.estimate(victims[0])
+}
+
+// victims approximates the eviction order: the next competition from the
+// window is decided as victim would, later ones aren't.
+func (policy *tinyLFU[K]) victims(n int) []K {
+	keys := make([]K, 0, n)
+	oldest := policy.window.Back()
+	if policy.window.Len() >= policy.windowLimit && oldest != nil && !policy.admits(oldest.Value.(K)) {
+		keys = append(keys, oldest.Value.(K))
+		oldest = oldest.Prev()
+	}
+	keys = append(keys, policy.main.victims(n-len(keys))...)
+	for ; oldest != nil && len(keys) < n; oldest = oldest.Prev() {
+		keys = append(keys, oldest.Value.(K))
+	}
+	return keys[:min(n, len(keys))]
+}
+
+const (
+	sketchDepth   = 4
+	sketchMaximum = 15
+)
+
+// frequencySketch is a count-min sketch of small saturating counters behind
+// a doorkeeper bitset, which absorbs keys that are only ever seen once.
+// Every 10 x itemLimit increments all counters are halved. Rows have about
+// 4 x itemLimit counters: with fewer, keys seen once or twice collide with
+// the popular ones often enough to push them out.
+type frequencySketch[K comparable] struct {
+	// maphash with a random seed, unless a test needs the same collisions
+	// on every run
+	hash       func(key K) uint64
+	mask       uint64
+	rows       [sketchDepth][]uint8
+	doorkeeper []uint64
+	increments int
+	resetAfter int
+}
+
+func newFrequencySketch[K comparable](itemLimit int64) *frequencySketch[K] {
+	width := uint64(1) << bits.Len64(uint64(4*max(itemLimit, 16))-1)
+	seed := maphash.MakeSeed()
+	sketch := &frequencySketch[K]{
+		hash:       func(key K) uint64 { return maphash.Comparable(seed, key) },
+		mask:       width - 1,
+		doorkeeper: make([]uint64, (width+63)/64),
+		resetAfter: int(10 * max(itemLimit, 16)),
+	}
+	for i := range sketch.rows {
+		sketch.rows[i] = make([]uint8, width)
+	}
+	return sketch
+}
+
+// index returns the counter of key in row.
+func (sketch *frequencySketch[K]) index(hash uint64, row int) uint64 {
+	return (hash + uint64(row)*(hash>>32|1)) & sketch.mask
+}
+
+func (sketch *frequencySketch[K]) increment(key K) {
+	hash := sketch.hash(key)
+	door := bits.RotateLeft64(hash, 32) & sketch.mask
+	if sketch.doorkeeper[door/64]&(1<<(door%64)) == 0 {
+		sketch.doorkeeper[door/64] |= 1 << (door % 64)
+	} else {
+		for row := range sketch.rows {
+			if counter := &sketch.rows[row][sketch.index(hash, row)]; *counter < sketchMaximum {
+				*counter++
+			}
+		}
+	}
+	if sketch.increments++; sketch.increments >= sketch.resetAfter {
+		sketch.reset()
+	}
+}
+
+func (sketch *frequencySketch[K]) estimate(key K) int {
+	hash := sketch.hash(key)
+	estimate := sketchMaximum
+	for row := range sketch.rows {
+		estimate = min(estimate, int(sketch.rows[row][sketch.index(hash, row)]))
+	}
+	door := bits.RotateLeft64(hash, 32) & sketch.mask
+	if sketch.doorkeeper[door/64]&(1<<(door%64)) != 0 {
+		estimate++
+	}
+	return estimate
+}
+
+func (sketch *frequencySketch[K]) reset() {
+	for row := range sketch.rows {
+		for i := range sketch.rows[row] {
+			sketch.rows[row][i] /= 2
+		}
+	}
+	clear(sketch.doorkeeper)
+	sketch.increments /= 2
+}
@@ -0,0 +1,133 @@
+package lru
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTinyLFUAdmission(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	config := LRUCacheConfig{ItemLimit: 100, Admission: TinyLFUAdmission, Logger: slog.New(slog.DiscardHandler)}
+
+	t.Run("serves a key right after it was set", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(config)
+		for i := 0; i < 1000; i++ {
+			key := fmt.Sprintf("user%d", i)
+			lruCache.Set(key, UserData{ID: i})
+			assert.True(t, lruCache.Has(key))
+		}
+		assert.Equal(t, 100, lruCache.(*InMemoryLRUCache[UserData]).Len())
+	})
+
+	t.Run("keeps popular keys through a flood of one-off keys", func(t *testing.T) {
+		for _, eviction := range []EvictionPolicy{LRUEviction, TwoQueueEviction} {
+			t.Run(eviction.String(), func(t *testing.T) {
+				floodConfig := config
+				floodConfig.Eviction = eviction
+				lruCache := cacheProvider.NewLRUCache(floodConfig).(*InMemoryLRUCache[UserData])
+				// A count-min sketch can overestimate a one-off key colliding
+				// with popular ones; a fixed hash makes the same collisions
+				// happen on every run.
+				lruCache.policy.(*tinyLFU[string]).sketch.hash = fnvHash
+				for i := 0; i < 50; i++ {
+					key := fmt.Sprintf("popular%d", i)
+					lruCache.Set(key, UserData{ID: i})
+					for j := 0; j < 5; j++ {
+						lruCache.Get(key)
+					}
+				}
+				// six times the cache, but within one sketch period: after
+				// the sketch halves its counters, popularity this old is meant
+				// to fade
+				for i := 0; i < 600; i++ {
+					lruCache.Set(fmt.Sprintf("oneoff%d", i), UserData{ID: i})
+				}
+				for i := 0; i < 50; i++ {
+					assert.True(t, lruCache.Has(fmt.Sprintf("popular%d", i)), "Key 'popular%d' should have been kept", i)
+				}
+			})
+		}
+	})
+
+	t.Run("plain LRU loses popular keys to the flood", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 100, Logger: slog.New(slog.DiscardHandler)})
+		lruCache.Set("popular0", UserData{})
+		for j := 0; j < 5; j++ {
+			lruCache.Get("popular0")
+		}
+		for i := 0; i < 1000; i++ {
+			lruCache.Set(fmt.Sprintf("oneoff%d", i), UserData{ID: i})
+		}
+		assert.False(t, lruCache.Has("popular0"))
+	})
+
+	t.Run("admits keys that were often missed", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+		for i := 0; i < 100; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+		}
+		for j := 0; j < 5; j++ {
+			lruCache.Get("wanted")
+		}
+		lruCache.Set("wanted", UserData{})
+		lruCache.Set("next", UserData{})
+		assert.True(t, lruCache.Has("wanted"), "A key missed often should win over a key never read")
+	})
+
+	t.Run("previews the next victim", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+		for i := 0; i < 100; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+		}
+		next := lruCache.PreviewEvictions(1)
+		assert.Len(t, lruCache.PreviewEvictions(200), 100)
+		lruCache.Set("new", UserData{})
+		assert.False(t, lruCache.Has(next[0]))
+	})
+}
+
+func fnvHash(key string) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	return hash.Sum64()
+}
+
+func TestFrequencySketch(t *testing.T) {
+	t.Run("estimates how often a key was seen", func(t *testing.T) {
+		sketch := newFrequencySketch[string](1000)
+		assert.Equal(t, 0, sketch.estimate("user1"))
+		for i := 0; i < 5; i++ {
+			sketch.increment("user1")
+		}
+		sketch.increment("user2")
+		assert.Equal(t, 5, sketch.estimate("user1"))
+		assert.Equal(t, 1, sketch.estimate("user2"), "The doorkeeper counts the first sighting")
+	})
+
+	t.Run("halves counts periodically", func(t *testing.T) {
+		sketch := newFrequencySketch[int](1000)
+		for i := 0; i < 9; i++ {
+			sketch.increment(0)
+		}
+		assert.Equal(t, 9, sketch.estimate(0))
+		sketch.reset()
+		assert.Equal(t, 4, sketch.estimate(0), "Counts should be halved and the doorkeeper cleared")
+
+		for i := 0; i < sketch.resetAfter; i++ {
+			sketch.increment(i)
+		}
+		assert.Less(t, sketch.increments, sketch.resetAfter)
+	})
+
+	t.Run("saturates counters", func(t *testing.T) {
+		sketch := newFrequencySketch[string](1000)
+		for i := 0; i < 100; i++ {
+			sketch.increment("user1")
+		}
+		assert.Equal(t, sketchMaximum+1, sketch.estimate("user1"))
+	})
+}
@@ -0,0 +1,75 @@
+package lru
+
+import "slices"
+
+// keyTrie indexes keys byte by byte, so prefix lookups only visit the keys
+// that match.
+type keyTrie struct {
+	root trieNode
+}
+
+type trieNode struct {
+	children map[byte]*trieNode
+	leaf     bool
+}
+
+func newKeyTrie() *keyTrie {
+	return &keyTrie{}
+}
+
+func (trie *keyTrie) insert(key string) {
+	node := &trie.root
+	for i := 0; i < len(key); i++ {
+		child, exists := node.children[key[i]]
+		if !exists {
+			if node.children == nil {
+				node.children = make(map[byte]*trieNode)
+			}
+			child = &trieNode{}
+			node.children[key[i]] = child
+		}
+		node = child
+	}
+	node.leaf = true
+}
+
+func (trie *keyTrie) remove(key string) {
+	trie.root.remove(key, 0)
+}
+
+// remove reports whether the node is now empty and can be pruned.
+func (node *trieNode) remove(key string, depth int) bool {
+	if depth == len(key) {
+		node.leaf = false
+	} else if child, exists := node.children[key[depth]]; exists && child.remove(key, depth+1) {
+		delete(node.children, key[depth])
+	}
+	return !node.leaf && len(node.children) == 0
+}
+
+// withPrefix returns the keys starting with prefix in lexical order.
+func (trie *keyTrie) withPrefix(prefix string) []string {
+	node := &trie.root
+	for i := 0; i < len(prefix) && node != nil; i++ {
+		node = node.children[prefix[i]]
+	}
+	var keys []string
+	if node != nil {
+		node.collect([]byte(prefix), &keys)
+	}
+	return keys
+}
+
+func (node *trieNode) collect(key []byte, keys *[]string) {
+	if node.leaf {
+		*keys = append(*keys, string(key))
+	}
+	next := make([]byte, 0, len(node.children))
+	for b := range node.children {
+		next = append(next, b)
+	}
+	slices.Sort(next)
+	for _, b := range next {
+		node.children[b].collect(append(key, b), keys)
+	}
+}
@@ -0,0 +1,25 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyTrie(t *testing.T) {
+	trie := newKeyTrie()
+	for _, key := range []string{"user:2", "user:1", "user", "session:1"} {
+		trie.insert(key)
+	}
+
+	assert.Equal(t, []string{"user", "user:1", "user:2"}, trie.withPrefix("user"))
+	assert.Equal(t, []string{"session:1"}, trie.withPrefix("s"))
+	assert.Empty(t, trie.withPrefix("admin"))
+	assert.Len(t, trie.withPrefix(""), 4)
+
+	trie.remove("user:1")
+	trie.remove("session:1")
+	trie.remove("missing")
+	assert.Equal(t, []string{"user", "user:2"}, trie.withPrefix("user"))
+	assert.NotContains(t, trie.root.children, byte('s'), "Empty branches should be pruned")
+}
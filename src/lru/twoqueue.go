@@ -0,0 +1,134 @@
+package lru
+
+import "container/list"
+
+// twoQueue implements TwoQueueEviction, with the queue sizes suggested in
+// the 2Q paper.
+type twoQueue[K comparable] struct {
+	inLimit    int
+	ghostLimit int
+	// newest first; in is FIFO, main is LRU
+	in     *list.List
+	main   *list.List
+	ghosts *list.List
+	// resident keys, and keys recently evicted from in
+	queued    map[K]twoQueueSlot
+	ghostKeys map[K]*list.Element
+}
+
+type twoQueueSlot struct {
+	element *list.Element
+	main    bool
+}
+
+func newTwoQueue[K comparable](itemLimit int64) *twoQueue[K] {
+	return &twoQueue[K]{
+		inLimit:    max(1, int(itemLimit/4)),
+		ghostLimit: max(1, int(itemLimit/2)),
+		in:         list.New(),
+		main:       list.New(),
+		ghosts:     list.New(),
+		queued:     make(map[K]twoQueueSlot),
+		ghostKeys:  make(map[K]*list.Element),
+	}
+}
+
+func (queue *twoQueue[K]) added(key K) {
+	if ghost, exists := queue.ghostKeys[key]; exists {
+		queue.ghosts.Remove(ghost)
+		delete(queue.ghostKeys, key)
+		queue.queued[key] = twoQueueSlot{element: queue.main.PushFront(key), main: true}
+		return
+	}
+	queue.queued[key] = twoQueueSlot{element: queue.in.PushFront(key)}
+}
+
+// accessed only reorders the main queue; hits on recently added keys are
+// expected and don't make them hot.
+func (queue *twoQueue[K]) accessed(key K) {
+	if slot, exists := queue.queued[key]; exists && slot.main {
+		queue.main.MoveToFront(slot.element)
+	}
+}
+
+func (queue *twoQueue[K]) removed(key K) {
+	slot, exists := queue.queued[key]
+	if !exists {
+		return
+	}
+	if slot.main {
+		queue.main.Remove(slot.element)
+	} else {
+		queue.in.Remove(slot.element)
+	}
+	delete(queue.queued, key)
+}
+
+func (queue *twoQueue[K]) renamed(oldKey, newKey K) {
+	slot, exists := queue.queued[oldKey]
+	if !exists {
+		return
+	}
+	if ghost, exists := queue.ghostKeys[newKey]; exists {
+		queue.ghosts.Remove(ghost)
+		delete(queue.ghostKeys, newKey)
+	}
+	slot.element.Value = newKey
+	queue.queued[newKey] = slot
+	delete(queue.queued, oldKey)
+}
+
+// victim takes from the main queue while the in queue is within its limit,
+// and falls back to the other queue when every key of the first is skipped.
+func (queue *twoQueue[K]) victim(skip func(key K) bool) (K, bool) {
+	fromIn := queue.in.Len() > queue.inLimit || queue.main.Len() == 0
+	if fromIn {
+		if oldest := oldestUnskipped(queue.in, skip); oldest != nil {
+			return queue.evictIn(oldest.Value.(K)), true
+		}
+	}
+	if oldest := oldestUnskipped(queue.main, skip); oldest != nil {
+		key := oldest.Value.(K)
+		queue.removed(key)
+		return key, true
+	}
+	if !fromIn {
+		if oldest := oldestUnskipped(queue.in, skip); oldest != nil {
+			return queue.evictIn(oldest.Value.(K)), true
+		}
+	}
+	var zero K
+	return zero, false
+}
+
+// evictIn removes key from the in queue and remembers it as a ghost.
+func (queue *twoQueue[K]) evictIn(key K) K {
+	queue.removed(key)
+	queue.remember(key)
+	return key
+}
+
+func (queue *twoQueue[K]) remember(key K) {
+	queue.ghostKeys[key] = queue.ghosts.PushFront(key)
+	for queue.ghosts.Len() > queue.ghostLimit {
+		oldest := queue.ghosts.Back()
+		queue.ghosts.Remove(oldest)
+		delete(queue.ghostKeys, oldest.Value.(K))
+	}
+}
+
+func (queue *twoQueue[K]) victims(n int) []K {
+	keys := make([]K, 0, min(n, len(queue.queued)))
+	in := queue.in.Back()
+	for excess := queue.in.Len() - queue.inLimit; excess > 0 && len(keys) < n; excess-- {
+		keys = append(keys, in.Value.(K))
+		in = in.Prev()
+	}
+	for element := queue.main.Back(); element != nil && len(keys) < n; element = element.Prev() {
+		keys = append(keys, element.Value.(K))
+	}
+	for ; in != nil && len(keys) < n; in = in.Prev() {
+		keys = append(keys, in.Value.(K))
+	}
+	return keys
+}
@@ -0,0 +1,97 @@
+package lru
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoQueueEviction(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	config := LRUCacheConfig{ItemLimit: 8, Eviction: TwoQueueEviction, Logger: slog.New(slog.DiscardHandler)}
+
+	t.Run("evicts new keys first, in insertion order", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+		for i := 0; i < 8; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+		}
+		lruCache.Get("user0")
+
+		lruCache.Set("user8", UserData{ID: 8})
+		assert.False(t, lruCache.Has("user0"), "Reads don't protect keys that were only seen once")
+		assert.True(t, lruCache.Has("user1"))
+	})
+
+	t.Run("admits keys that come back after eviction to the main queue", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+		for i := 0; i < 9; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+		}
+		assert.False(t, lruCache.Has("user0"))
+		lruCache.Set("user0", UserData{ID: 0})
+		assert.False(t, lruCache.Has("user1"), "Admitting user0 evicted the oldest new key")
+		assert.Equal(t, []string{"user2", "user3", "user4", "user5", "user6", "user0", "user7", "user8"}, lruCache.PreviewEvictions(10),
+			"The main queue is only evicted from once the FIFO queue is back to its share")
+	})
+
+	t.Run("keeps the hot set through a scan", func(t *testing.T) {
+		scanConfig := config
+		scanConfig.ItemLimit = 20
+		lruCache := cacheProvider.NewLRUCache(scanConfig).(*InMemoryLRUCache[UserData])
+		hot := []string{"hot0", "hot1", "hot2", "hot3"}
+		for _, key := range hot {
+			lruCache.Set(key, UserData{})
+		}
+		for i := 0; i < 20; i++ {
+			lruCache.Set(fmt.Sprintf("filler%d", i), UserData{ID: i})
+		}
+		for _, key := range hot {
+			assert.False(t, lruCache.Has(key))
+			lruCache.Set(key, UserData{})
+		}
+
+		for i := 0; i < 1000; i++ {
+			lruCache.Set(fmt.Sprintf("scan%d", i), UserData{ID: i})
+		}
+		for _, key := range hot {
+			assert.True(t, lruCache.Has(key), "Key %q should survive the scan", key)
+		}
+		assert.Equal(t, 20, lruCache.Len())
+	})
+
+	t.Run("plain LRU loses the hot set to a scan", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 8, Logger: slog.New(slog.DiscardHandler)})
+		lruCache.Set("hot0", UserData{})
+		lruCache.Get("hot0")
+		for i := 0; i < 8; i++ {
+			lruCache.Set(fmt.Sprintf("scan%d", i), UserData{ID: i})
+		}
+		assert.False(t, lruCache.Has("hot0"))
+	})
+
+	t.Run("forgets deleted keys", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+		lruCache.Delete("user1")
+		assert.Equal(t, []string{"user2"}, lruCache.PreviewEvictions(10))
+
+		lruCache.Clear()
+		assert.Empty(t, lruCache.PreviewEvictions(10))
+		for i := 0; i < 20; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+		}
+		assert.Equal(t, 8, lruCache.Len())
+	})
+
+	t.Run("survives snapshots", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+		for i := 0; i < 4; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+		}
+		lruCache.SwapAll(map[string]UserData{"user1": {ID: 1}, "user2": {ID: 2}})
+		assert.Len(t, lruCache.PreviewEvictions(10), 2)
+	})
+}
@@ -0,0 +1,32 @@
+package lru
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypeMismatchError is returned by GetAs when the cached value has a
+// different type than requested.
+type TypeMismatchError struct {
+	Key  string
+	Want reflect.Type
+	Got  reflect.Type
+}
+
+func (err *TypeMismatchError) Error() string {
+	return fmt.Sprintf("LRU cache value for key %q is %v, not %v", err.Key, err.Got, err.Want)
+}
+
+// GetAs fetches key from a heterogeneous cache and asserts its value to V.
+func GetAs[V any](cache CacheReader[any], key string) (V, error) {
+	var zero V
+	value, err := cache.Get(key)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := value.(V)
+	if !ok {
+		return zero, &TypeMismatchError{Key: key, Want: reflect.TypeFor[V](), Got: reflect.TypeOf(value)}
+	}
+	return typed, nil
+}
@@ -0,0 +1,45 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAs(t *testing.T) {
+	lruCache := InMemoryLRUCacheProvider[any]{}.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+	lruCache.Set("user1", UserData{ID: 1, Name: "Alice", Age: 30})
+	lruCache.Set("count", 42)
+	lruCache.Set("greeter", fmt.Stringer(nil))
+
+	t.Run("returns values of the requested type", func(t *testing.T) {
+		user, err := GetAs[UserData](lruCache, "user1")
+		assert.NoError(t, err)
+		assert.Equal(t, UserData{ID: 1, Name: "Alice", Age: 30}, user)
+
+		count, err := GetAs[int](lruCache, "count")
+		assert.NoError(t, err)
+		assert.Equal(t, 42, count)
+	})
+
+	t.Run("returns a typed error on mismatch", func(t *testing.T) {
+		count, err := GetAs[string](lruCache, "count")
+		assert.Empty(t, count)
+		var mismatch *TypeMismatchError
+		assert.True(t, errors.As(err, &mismatch))
+		assert.Equal(t, "count", mismatch.Key)
+		assert.EqualError(t, err, `LRU cache value for key "count" is int, not string`)
+
+		_, err = GetAs[fmt.Stringer](lruCache, "greeter")
+		assert.EqualError(t, err, `LRU cache value for key "greeter" is <nil>, not fmt.Stringer`)
+	})
+
+	t.Run("passes through cache misses", func(t *testing.T) {
+		_, err := GetAs[UserData](lruCache, "user2")
+		assert.Error(t, err)
+		var mismatch *TypeMismatchError
+		assert.False(t, errors.As(err, &mismatch))
+	})
+}
@@ -0,0 +1,55 @@
+package lru
+
+// UnionView reads from several caches in priority order, for instance while
+// migrating from one cache to another. It never writes to them.
+type UnionView[T any] struct {
+	caches []CacheReader[T]
+}
+
+// NewUnionView returns a view of caches, the first having priority. Wrap
+// caches keyed differently from the others with MapKeys.
+func NewUnionView[T any](caches ...CacheReader[T]) *UnionView[T] {
+	return &UnionView[T]{caches: caches}
+}
+
+// Has reports whether any of the caches has key.
+func (view *UnionView[T]) Has(key string) bool {
+	for _, cache := range view.caches {
+		if cache.Has(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the value of key from the first cache that has it, or the
+// error of the last cache if none does.
+func (view *UnionView[T]) Get(key string) (T, error) {
+	err := ErrKeyNotFound
+	for _, cache := range view.caches {
+		var value T
+		if value, err = cache.Get(key); err == nil {
+			return value, nil
+		}
+	}
+	var zero T
+	return zero, err
+}
+
+type mappedKeys[T any] struct {
+	cache  CacheReader[T]
+	mapKey func(key string) string
+}
+
+// MapKeys returns a reader that looks keys up in cache as mapKey(key).
+func MapKeys[T any](cache CacheReader[T], mapKey func(key string) string) CacheReader[T] {
+	return mappedKeys[T]{cache: cache, mapKey: mapKey}
+}
+
+func (reader mappedKeys[T]) Has(key string) bool {
+	return reader.cache.Has(reader.mapKey(key))
+}
+
+func (reader mappedKeys[T]) Get(key string) (T, error) {
+	return reader.cache.Get(reader.mapKey(key))
+}
@@ -0,0 +1,55 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnionView(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	alice := UserData{ID: 1, Name: "Alice", Age: 30}
+	bob := UserData{ID: 2, Name: "Bob", Age: 25}
+
+	t.Run("reads from the caches in priority order", func(t *testing.T) {
+		current := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		legacy := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		current.Set("user1", alice)
+		legacy.Set("user1", bob)
+		legacy.Set("user2", bob)
+		view := NewUnionView[UserData](current, legacy)
+
+		value, err := view.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, alice, value)
+		value, err = view.Get("user2")
+		assert.NoError(t, err)
+		assert.Equal(t, bob, value)
+		assert.True(t, view.Has("user2"))
+
+		value, err = view.Get("user3")
+		assert.Error(t, err)
+		assert.Empty(t, value)
+		assert.False(t, view.Has("user3"))
+	})
+
+	t.Run("maps keys of differently keyed caches", func(t *testing.T) {
+		current := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		legacy := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10})
+		legacy.Set("user:1", alice)
+		view := NewUnionView(current, MapKeys[UserData](legacy, func(key string) string { return "user:" + key }))
+
+		value, err := view.Get("1")
+		assert.NoError(t, err)
+		assert.Equal(t, alice, value)
+		assert.True(t, view.Has("1"))
+		assert.False(t, view.Has("user:1"))
+	})
+
+	t.Run("misses when empty", func(t *testing.T) {
+		view := NewUnionView[UserData]()
+		_, err := view.Get("user1")
+		assert.Error(t, err)
+		assert.False(t, view.Has("user1"))
+	})
+}
@@ -0,0 +1,125 @@
+package lru
+
+import (
+	"container/list"
+	"time"
+)
+
+// victimCache holds the entries last evicted for capacity, see
+// Config.VictimCacheSize. It is guarded by Storage.mu.
+type victimCache[K comparable, V any] struct {
+	limit int64
+	// newest first
+	order *list.List
+	items map[K]*list.Element
+}
+
+type victimEntry[K comparable, V any] struct {
+	key  K
+	item *StorageItem[V]
+}
+
+func (cache *LRUCache[K, V]) newVictimCache() *victimCache[K, V] {
+	if cache.Config.VictimCacheSize <= 0 {
+		return nil
+	}
+	return &victimCache[K, V]{
+		limit: cache.Config.VictimCacheSize,
+		order: list.New(),
+		items: make(map[K]*list.Element),
+	}
+}
+
+// add returns the entry add pushed out, if the victim cache was full.
+func (victims *victimCache[K, V]) add(key K, item *StorageItem[V]) (victimEntry[K, V], bool) {
+	victims.items[key] = victims.order.PushFront(victimEntry[K, V]{key, item})
+	if int64(victims.order.Len()) <= victims.limit {
+		return victimEntry[K, V]{}, false
+	}
+	oldest := victims.order.Remove(victims.order.Back()).(victimEntry[K, V])
+	delete(victims.items, oldest.key)
+	return oldest, true
+}
+
+func (victims *victimCache[K, V]) take(key K) (*StorageItem[V], bool) {
+	element, exists := victims.items[key]
+	if !exists {
+		return nil, false
+	}
+	victims.order.Remove(element)
+	delete(victims.items, key)
+	return element.Value.(victimEntry[K, V]).item, true
+}
+
+func (victims *victimCache[K, V]) entries() []victimEntry[K, V] {
+	entries := make([]victimEntry[K, V], 0, victims.order.Len())
+	for element := victims.order.Front(); element != nil; element = element.Next() {
+		entries = append(entries, element.Value.(victimEntry[K, V]))
+	}
+	return entries
+}
+
+// retire moves an entry evicted for capacity to the victim cache and
+// returns what actually left the cache: the entry itself without a victim
+// cache, otherwise the one it pushed out, if any. Callers must hold the
+// write lock.
+func (cache *LRUCache[K, V]) retire(key K, item *StorageItem[V]) []Entry[K, V] {
+	if cache.victims == nil {
+		cache.dropAliases(key)
+		return []Entry[K, V]{{Key: key, Value: item.Value}}
+	}
+	if oldest, pushedOut := cache.victims.add(key, item); pushedOut {
+		cache.dropAliases(oldest.key)
+		return []Entry[K, V]{{Key: oldest.key, Value: oldest.item.Value}}
+	}
+	return nil
+}
+
+// readmit moves key back from the victim cache, unless it expired there.
+// Callers must hold the write lock and call notifyRemoved afterwards.
+func (cache *LRUCache[K, V]) readmit(key K, now time.Time) (*StorageItem[V], bool) {
+	if cache.victims == nil {
+		return nil, false
+	}
+	item, exists := cache.victims.take(key)
+	if !exists {
+		return nil, false
+	}
+	if cache.expired(item, now) {
+		cache.recordRemoval(key, item.Value, Expired)
+		cache.dropAliases(key)
+		return nil, false
+	}
+	if cache.full() {
+		// taking key made room in the victim cache for the entry evicted
+		// here. If every candidate is pinned, nothing is, and the cache
+		// stays over ItemLimit until the last Release.
+		for _, entry := range cache.removeOldestKey() {
+			cache.recordRemoval(entry.Key, entry.Value, CapacityEvicted)
+		}
+	}
+	item.element = cache.order.PushFront(key)
+	if cache.policy != nil {
+		cache.policy.added(key)
+	}
+	if cache.index != nil {
+		cache.index.insert(any(key).(string))
+	}
+	item.expiryTick = cache.wheel.schedule(key, 0, item.DeleteAt)
+	cache.Storage.SafeMap[key] = item
+	cache.publish(key, item)
+	cache.stats.prematureEvictions.Add(1)
+	return item, true
+}
+
+// forgetVictim drops key from the victim cache, so that it can't come back
+// after being written, deleted or renamed over. Callers must hold the write
+// lock and call notifyRemoved afterwards.
+func (cache *LRUCache[K, V]) forgetVictim(key K, reason EvictionReason) {
+	if cache.victims == nil {
+		return
+	}
+	if item, exists := cache.victims.take(key); exists {
+		cache.recordRemoval(key, item.Value, reason)
+	}
+}
@@ -0,0 +1,99 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheVictimCache(t *testing.T) {
+	alice := UserData{ID: 1, Name: "Alice", Age: 30}
+	bob := UserData{ID: 2, Name: "Bob", Age: 25}
+	newCache := func(config LRUCacheConfig, hooks Hooks[string, UserData]) *InMemoryLRUCache[UserData] {
+		return InMemoryLRUCacheProvider[UserData]{Hooks: hooks}.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+	}
+
+	t.Run("readmits recently evicted entries", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 2, VictimCacheSize: 2}, Hooks[string, UserData]{})
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", bob)
+		lruCache.Set("user3", bob)
+		assert.Equal(t, 2, lruCache.Len())
+
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, alice, value)
+		assert.Equal(t, []string{"user1", "user3"}, lruCache.Keys())
+
+		stats := lruCache.Stats()
+		assert.Equal(t, uint64(1), stats.PrematureEvictions)
+		assert.Equal(t, uint64(2), stats.Evictions)
+	})
+
+	t.Run("forgets the oldest victims", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1, VictimCacheSize: 1}, Hooks[string, UserData]{})
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", bob)
+		lruCache.Set("user3", bob)
+		_, err := lruCache.Get("user1")
+		assert.Error(t, err)
+		assert.True(t, lruCache.Has("user2"))
+		assert.Equal(t, uint64(1), lruCache.Stats().PrematureEvictions)
+	})
+
+	t.Run("only reports entries leaving the victim cache", func(t *testing.T) {
+		var evicted []string
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1, VictimCacheSize: 1}, Hooks[string, UserData]{OnEvict: func(key string, value UserData, reason EvictionReason) {
+			evicted = append(evicted, key+" "+reason.String())
+		}})
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", bob)
+		assert.Empty(t, evicted)
+		lruCache.Set("user3", bob)
+		assert.Equal(t, []string{"user1 capacity"}, evicted)
+		lruCache.Clear()
+		assert.ElementsMatch(t, []string{"user1 capacity", "user2 cleared", "user3 cleared"}, evicted)
+	})
+
+	t.Run("doesn't bring back deleted or overwritten values", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1, VictimCacheSize: 3, PrefixIndex: true}, Hooks[string, UserData]{})
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", bob)
+		assert.False(t, lruCache.Delete("user1"))
+		_, err := lruCache.Get("user1")
+		assert.Error(t, err)
+
+		lruCache.Set("user3", bob)
+		lruCache.Set("user2", alice)
+		value, _ := lruCache.Get("user2")
+		assert.Equal(t, alice, value)
+
+		lruCache.Set("admin1", bob)
+		assert.Equal(t, 0, lruCache.DeletePrefix("user"))
+		assert.False(t, lruCache.Has("user3"))
+		assert.False(t, lruCache.Has("user2"))
+		assert.Equal(t, uint64(0), lruCache.Stats().PrematureEvictions)
+	})
+
+	t.Run("doesn't readmit expired entries", func(t *testing.T) {
+		lruCache := newCache(LRUCacheConfig{ItemLimit: 1, VictimCacheSize: 1, TTL: 50}, Hooks[string, UserData]{})
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", bob)
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, lruCache.Has("user1"))
+		assert.Equal(t, uint64(0), lruCache.Stats().PrematureEvictions)
+	})
+
+	t.Run("works with eviction policies", func(t *testing.T) {
+		for _, eviction := range []EvictionPolicy{TwoQueueEviction, FIFOEviction, ClockEviction, SegmentedLRUEviction} {
+			lruCache := newCache(LRUCacheConfig{ItemLimit: 2, VictimCacheSize: 2, Eviction: eviction}, Hooks[string, UserData]{})
+			lruCache.Set("user1", alice)
+			lruCache.Set("user2", bob)
+			lruCache.Set("user3", bob)
+			assert.True(t, lruCache.Has("user1"), eviction.String())
+			assert.Equal(t, 2, lruCache.Len(), eviction.String())
+			assert.Len(t, lruCache.PreviewEvictions(3), 2, eviction.String())
+		}
+	})
+}
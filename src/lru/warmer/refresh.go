@@ -0,0 +1,157 @@
+package warmer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"lru"
+)
+
+// Window is a daily maintenance window, e.g. {Start: 2 * time.Hour,
+// Length: time.Hour} for 02:00 to 03:00.
+type Window struct {
+	// Start is the time of day the window opens, as an offset from midnight.
+	Start  time.Duration
+	Length time.Duration
+	// Location of midnight, time.Local if nil.
+	Location *time.Location
+}
+
+// next returns the window that is open at t, or else the next one to open.
+func (window Window) next(t time.Time) (start, end time.Time) {
+	location := window.Location
+	if location == nil {
+		location = time.Local
+	}
+	local := t.In(location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	for day := -1; ; day++ {
+		start = midnight.AddDate(0, 0, day).Add(window.Start)
+		if end = start.Add(window.Length); t.Before(end) {
+			return start, end
+		}
+	}
+}
+
+type RefreshConfig struct {
+	// Windows during which Start refreshes the cache. Windows with a Length
+	// of zero or less are ignored.
+	Windows []Window
+	// Rate caps reloads per second, 10 if zero.
+	Rate float64
+	// Match picks the keys to refresh, e.g. by prefix. All keys if nil.
+	Match func(key string) bool
+	// OnError receives the keys whose reload failed. They keep their
+	// current value until they expire.
+	OnError func(key string, err error)
+}
+
+// RefreshCache is implemented by InMemoryLRUCache.
+type RefreshCache[T any] interface {
+	Keys() []string
+	Peek(key string) (T, bool)
+	SetWithProvenance(key string, value T, provenance lru.Provenance) T
+}
+
+// Refresher reloads the entries of a cache key by key during maintenance
+// windows, so that changes made to the source at a known time reach the
+// cache then, rather than whenever each entry happens to expire.
+type Refresher[T any] struct {
+	cache  RefreshCache[T]
+	load   func(ctx context.Context, key string) (T, error)
+	config RefreshConfig
+}
+
+func NewRefresher[T any](cache RefreshCache[T], load func(ctx context.Context, key string) (T, error), config RefreshConfig) *Refresher[T] {
+	if config.Rate <= 0 {
+		config.Rate = 10
+	}
+	return &Refresher[T]{cache: cache, load: load, config: config}
+}
+
+// Refresh reloads every matching key once, now, at no more than Rate keys
+// per second. It only updates keys that are still cached, so entries
+// evicted in the meantime aren't loaded back. Refreshed values are fills
+// with "refresh" as their Provenance.Loader. Refresh returns ctx.Err() if ctx
+// is done before every key was reloaded.
+func (refresher *Refresher[T]) Refresh(ctx context.Context) error {
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / refresher.config.Rate))
+	defer ticker.Stop()
+	first := true
+	for _, key := range refresher.cache.Keys() {
+		if refresher.config.Match != nil && !refresher.config.Match(key) {
+			continue
+		}
+		if _, cached := refresher.cache.Peek(key); !cached {
+			continue
+		}
+		if !first {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		first = false
+		start := time.Now()
+		value, err := refresher.load(ctx, key)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if refresher.config.OnError != nil {
+				refresher.config.OnError(key, err)
+			}
+			continue
+		}
+		if _, cached := refresher.cache.Peek(key); cached {
+			refresher.cache.SetWithProvenance(key, value, lru.Provenance{Loader: "refresh", LoadDuration: time.Since(start)})
+		}
+	}
+	return nil
+}
+
+// nextWindow returns the earliest window that is open at t or opens after
+// it.
+func (refresher *Refresher[T]) nextWindow(t time.Time) (start, end time.Time, ok bool) {
+	for _, window := range refresher.config.Windows {
+		if window.Length <= 0 {
+			continue
+		}
+		windowStart, windowEnd := window.next(t)
+		if !ok || windowStart.Before(start) {
+			start, end, ok = windowStart, windowEnd, true
+		}
+	}
+	return start, end, ok
+}
+
+// Start runs Refresh once during every window until stop is called or ctx
+// is done. A refresh still running when its window closes is cut short, and
+// starts over in the next window.
+func (refresher *Refresher[T]) Start(ctx context.Context) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		after := time.Now()
+		for {
+			start, end, ok := refresher.nextWindow(after)
+			if !ok {
+				return
+			}
+			timer := time.NewTimer(time.Until(start))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+			windowCtx, cancelWindow := context.WithDeadline(ctx, end)
+			refresher.Refresh(windowCtx)
+			cancelWindow()
+			after = end
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(cancel) }
+}
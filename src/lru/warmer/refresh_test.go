@@ -0,0 +1,137 @@
+package warmer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"lru"
+)
+
+func TestWindow(t *testing.T) {
+	window := Window{Start: 2 * time.Hour, Length: time.Hour, Location: time.UTC}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	t.Run("finds the open window", func(t *testing.T) {
+		start, end := window.next(at(10, 2, 30))
+		assert.Equal(t, at(10, 2, 0), start)
+		assert.Equal(t, at(10, 3, 0), end)
+	})
+
+	t.Run("finds the next window", func(t *testing.T) {
+		start, _ := window.next(at(10, 1, 0))
+		assert.Equal(t, at(10, 2, 0), start)
+		start, _ = window.next(at(10, 3, 0))
+		assert.Equal(t, at(11, 2, 0), start)
+	})
+
+	t.Run("handles windows spanning midnight", func(t *testing.T) {
+		late := Window{Start: 23 * time.Hour, Length: 2 * time.Hour, Location: time.UTC}
+		start, end := late.next(at(11, 0, 30))
+		assert.Equal(t, at(10, 23, 0), start)
+		assert.Equal(t, at(11, 1, 0), end)
+	})
+}
+
+func TestRefresher(t *testing.T) {
+	newCache := func() *lru.InMemoryLRUCache[string] {
+		lruCache := lru.InMemoryLRUCacheProvider[string]{}.NewLRUCache(lru.LRUCacheConfig{ItemLimit: 10}).(*lru.InMemoryLRUCache[string])
+		lruCache.Set("user:1", "old")
+		lruCache.Set("user:2", "old")
+		lruCache.Set("group:1", "old")
+		return lruCache
+	}
+	load := func(ctx context.Context, key string) (string, error) {
+		return "new", nil
+	}
+	// a window that is open now
+	openWindow := func(length time.Duration) Window {
+		now := time.Now()
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		return Window{Start: now.Sub(midnight), Length: length}
+	}
+
+	t.Run("reloads matching keys", func(t *testing.T) {
+		lruCache := newCache()
+		refresher := NewRefresher[string](lruCache, load, RefreshConfig{
+			Rate:  1000,
+			Match: func(key string) bool { return strings.HasPrefix(key, "user:") },
+		})
+		assert.NoError(t, refresher.Refresh(context.Background()))
+		value, _ := lruCache.Peek("user:1")
+		assert.Equal(t, "new", value)
+		value, _ = lruCache.Peek("group:1")
+		assert.Equal(t, "old", value)
+
+		info, _ := lruCache.EntryInfo("user:2")
+		assert.Equal(t, "refresh", info.Provenance.Loader)
+		assert.Equal(t, uint64(2), lruCache.Stats().Fills)
+	})
+
+	t.Run("keeps to the rate", func(t *testing.T) {
+		lruCache := newCache()
+		refresher := NewRefresher[string](lruCache, load, RefreshConfig{Rate: 20})
+		start := time.Now()
+		assert.NoError(t, refresher.Refresh(context.Background()))
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	})
+
+	t.Run("keeps the current value when a reload fails", func(t *testing.T) {
+		lruCache := newCache()
+		var failed []string
+		refresher := NewRefresher[string](lruCache, func(ctx context.Context, key string) (string, error) {
+			return "", errors.New("backend down")
+		}, RefreshConfig{Rate: 1000, OnError: func(key string, err error) { failed = append(failed, key) }})
+		assert.NoError(t, refresher.Refresh(context.Background()))
+		assert.ElementsMatch(t, []string{"user:1", "user:2", "group:1"}, failed)
+		value, _ := lruCache.Peek("user:1")
+		assert.Equal(t, "old", value)
+	})
+
+	t.Run("doesn't load back keys deleted meanwhile", func(t *testing.T) {
+		lruCache := newCache()
+		refresher := NewRefresher[string](lruCache, func(ctx context.Context, key string) (string, error) {
+			lruCache.Delete(key)
+			return "new", nil
+		}, RefreshConfig{Rate: 1000})
+		assert.NoError(t, refresher.Refresh(context.Background()))
+		assert.Equal(t, 0, lruCache.Len())
+	})
+
+	t.Run("refreshes during windows only", func(t *testing.T) {
+		lruCache := newCache()
+		var mu sync.Mutex
+		loads := 0
+		refresher := NewRefresher[string](lruCache, func(ctx context.Context, key string) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			loads++
+			return "new", nil
+		}, RefreshConfig{Rate: 10, Windows: []Window{openWindow(150 * time.Millisecond)}})
+		stop := refresher.Start(context.Background())
+		defer stop()
+
+		time.Sleep(400 * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		// one load right away and one more per 100ms until the window closes
+		assert.Equal(t, 2, loads)
+	})
+
+	t.Run("doesn't start without windows", func(t *testing.T) {
+		refresher := NewRefresher[string](newCache(), func(ctx context.Context, key string) (string, error) {
+			t.Error("unexpected reload")
+			return "", nil
+		}, RefreshConfig{Windows: []Window{{Start: time.Hour}}})
+		stop := refresher.Start(context.Background())
+		time.Sleep(50 * time.Millisecond)
+		stop()
+	})
+}
@@ -0,0 +1,165 @@
+package warmer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"lru"
+)
+
+// Source loads the full contents of a lookup table.
+type Source[T any] interface {
+	Load(ctx context.Context) (map[string]T, error)
+}
+
+type SourceFunc[T any] func(ctx context.Context) (map[string]T, error)
+
+func (load SourceFunc[T]) Load(ctx context.Context) (map[string]T, error) {
+	return load(ctx)
+}
+
+// SQLSource runs Query and maps every row with Scan.
+type SQLSource[T any] struct {
+	DB    *sql.DB
+	Query string
+	Args  []any
+	Scan  func(rows *sql.Rows) (key string, value T, err error)
+}
+
+func (source SQLSource[T]) Load(ctx context.Context) (map[string]T, error) {
+	rows, err := source.DB.QueryContext(ctx, source.Query, source.Args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying warm-up source: %w", err)
+	}
+	defer rows.Close()
+	entries := make(map[string]T)
+	for rows.Next() {
+		key, value, err := source.Scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning warm-up row: %w", err)
+		}
+		entries[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading warm-up rows: %w", err)
+	}
+	return entries, nil
+}
+
+// HTTPSource fetches URL, decodes its body as a JSON array of records and
+// maps each one with Key and Value.
+type HTTPSource[R, T any] struct {
+	URL string
+	// Client is http.DefaultClient if nil.
+	Client *http.Client
+	Key    func(record R) string
+	Value  func(record R) T
+}
+
+func (source HTTPSource[R, T]) Load(ctx context.Context) (map[string]T, error) {
+	client := source.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %s", source.URL, resp.Status)
+	}
+	var records []R
+	if err := json.NewDecoder(resp.Body).Decode(&records); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", source.URL, err)
+	}
+	entries := make(map[string]T, len(records))
+	for _, record := range records {
+		entries[source.Key(record)] = source.Value(record)
+	}
+	return entries, nil
+}
+
+type Config struct {
+	// Interval between reloads started with Start. Zero only loads once.
+	Interval time.Duration
+	// OnError receives the errors of scheduled reloads. The cache keeps its
+	// previous contents when a reload fails.
+	OnError func(err error)
+	// Background runs the scheduled reloads instead of a goroutine of their
+	// own.
+	Background *lru.Background
+}
+
+// Warmer populates a cache from a Source at startup and on a schedule.
+type Warmer[T any] struct {
+	cache  lru.CacheWriter[T]
+	source Source[T]
+	config Config
+}
+
+func New[T any](cache lru.CacheWriter[T], source Source[T], config Config) *Warmer[T] {
+	return &Warmer[T]{cache: cache, source: source, config: config}
+}
+
+// Warm loads the source once. Caches with SwapAll, such as
+// InMemoryLRUCache, have their contents replaced atomically, so rows removed
+// from the source disappear from the cache too. Other caches get one Set per
+// entry.
+func (warmer *Warmer[T]) Warm(ctx context.Context) error {
+	entries, err := warmer.source.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if swapper, ok := warmer.cache.(interface{ SwapAll(map[string]T) }); ok {
+		swapper.SwapAll(entries)
+		return nil
+	}
+	for key, value := range entries {
+		warmer.cache.Set(key, value)
+	}
+	return nil
+}
+
+// Start warms the cache and, if it succeeds, keeps reloading it every
+// Interval until stop is called or ctx is done.
+func (warmer *Warmer[T]) Start(ctx context.Context) (stop func(), err error) {
+	if err := warmer.Warm(ctx); err != nil {
+		return func() {}, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	if warmer.config.Interval > 0 && warmer.config.Background != nil {
+		stopReloads := warmer.config.Background.Every(warmer.config.Interval, func() {
+			if err := warmer.Warm(ctx); err != nil && warmer.config.OnError != nil {
+				warmer.config.OnError(err)
+			}
+		})
+		context.AfterFunc(ctx, stopReloads)
+	} else if warmer.config.Interval > 0 {
+		go func() {
+			ticker := time.NewTicker(warmer.config.Interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					if err := warmer.Warm(ctx); err != nil && warmer.config.OnError != nil {
+						warmer.config.OnError(err)
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	var once sync.Once
+	return func() { once.Do(cancel) }, nil
+}
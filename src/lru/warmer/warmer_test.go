@@ -0,0 +1,161 @@
+package warmer
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"lru"
+)
+
+type country struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// fakeDriver answers every query with the rows in fakeTable.
+type fakeDriver struct{}
+type fakeConn struct{}
+type fakeStmt struct{}
+type fakeRows struct{ rows [][]driver.Value }
+
+var fakeTable struct {
+	sync.Mutex
+	rows [][]driver.Value
+}
+
+func (fakeDriver) Open(name string) (driver.Conn, error)   { return fakeConn{}, nil }
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+func (fakeStmt) Close() error                              { return nil }
+func (fakeStmt) NumInput() int                             { return -1 }
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	fakeTable.Lock()
+	defer fakeTable.Unlock()
+	return &fakeRows{rows: append([][]driver.Value(nil), fakeTable.rows...)}, nil
+}
+func (*fakeRows) Columns() []string { return []string{"code", "name"} }
+func (*fakeRows) Close() error      { return nil }
+func (rows *fakeRows) Next(dest []driver.Value) error {
+	if len(rows.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, rows.rows[0])
+	rows.rows = rows.rows[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("warmer-fake", fakeDriver{})
+}
+
+func newCache() lru.LRUCacher[string] {
+	return lru.InMemoryLRUCacheProvider[string]{}.NewLRUCache(lru.LRUCacheConfig{ItemLimit: 100})
+}
+
+func TestWarmer(t *testing.T) {
+	t.Run("populates the cache from SQL", func(t *testing.T) {
+		fakeTable.Lock()
+		fakeTable.rows = [][]driver.Value{{"de", "Germany"}, {"fr", "France"}}
+		fakeTable.Unlock()
+		db, err := sql.Open("warmer-fake", "")
+		assert.NoError(t, err)
+		defer db.Close()
+
+		cache := newCache()
+		source := SQLSource[string]{DB: db, Query: "SELECT code, name FROM countries", Scan: func(rows *sql.Rows) (string, string, error) {
+			var code, name string
+			err := rows.Scan(&code, &name)
+			return code, name, err
+		}}
+		assert.NoError(t, New(cache, source, Config{}).Warm(context.Background()))
+
+		value, err := cache.Get("de")
+		assert.NoError(t, err)
+		assert.Equal(t, "Germany", value)
+		assert.True(t, cache.Has("fr"))
+	})
+
+	t.Run("reloads from HTTP on a schedule", func(t *testing.T) {
+		var countries atomic.Pointer[[]country]
+		countries.Store(&[]country{{Code: "de", Name: "Germany"}, {Code: "fr", Name: "France"}})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(*countries.Load())
+		}))
+		defer server.Close()
+
+		cache := newCache()
+		source := HTTPSource[country, string]{
+			URL:   server.URL,
+			Key:   func(record country) string { return record.Code },
+			Value: func(record country) string { return record.Name },
+		}
+		stop, err := New(cache, source, Config{Interval: 50 * time.Millisecond}).Start(context.Background())
+		assert.NoError(t, err)
+		defer stop()
+		assert.True(t, cache.Has("fr"), "Start should warm the cache before returning")
+
+		countries.Store(&[]country{{Code: "de", Name: "Deutschland"}})
+		assert.Eventually(t, func() bool {
+			value, _ := cache.Get("de")
+			return value == "Deutschland"
+		}, time.Second, 10*time.Millisecond)
+		assert.False(t, cache.Has("fr"), "Rows removed from the source should leave the cache")
+	})
+
+	t.Run("keeps the previous contents when a reload fails", func(t *testing.T) {
+		var failing atomic.Bool
+		source := SourceFunc[string](func(ctx context.Context) (map[string]string, error) {
+			if failing.Load() {
+				return nil, errors.New("source down")
+			}
+			return map[string]string{"de": "Germany"}, nil
+		})
+		errs := make(chan error, 1)
+		cache := newCache()
+		stop, err := New(cache, source, Config{Interval: 20 * time.Millisecond, OnError: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		}}).Start(context.Background())
+		assert.NoError(t, err)
+		defer stop()
+
+		failing.Store(true)
+		assert.EqualError(t, <-errs, "source down")
+		assert.True(t, cache.Has("de"))
+	})
+
+	t.Run("fails to start when the first load fails", func(t *testing.T) {
+		source := SourceFunc[string](func(ctx context.Context) (map[string]string, error) {
+			return nil, errors.New("source down")
+		})
+		_, err := New(newCache(), source, Config{Interval: time.Second}).Start(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("sets each entry on caches without SwapAll", func(t *testing.T) {
+		cache := &lru.TieredLRUCache[string]{Tiers: []lru.LRUCacher[string]{newCache()}}
+		source := SourceFunc[string](func(ctx context.Context) (map[string]string, error) {
+			return map[string]string{"de": "Germany"}, nil
+		})
+		assert.NoError(t, New[string](cache, source, Config{}).Warm(context.Background()))
+		assert.True(t, cache.Has("de"))
+	})
+}
@@ -0,0 +1,113 @@
+package lru
+
+import "time"
+
+const (
+	defaultExpiryTick = 50 * time.Millisecond
+	defaultSweepBatch = 1000
+)
+
+// expiryWheel buckets keys by the tick their entry expires in, so a sweep
+// drops whole buckets of due keys instead of scanning the cache. Callers
+// must hold the storage write lock.
+type expiryWheel[K comparable] struct {
+	tick    time.Duration
+	buckets map[int64]map[K]struct{}
+	// buckets before it have been dropped
+	cursor int64
+}
+
+func newExpiryWheel[K comparable](tick time.Duration) expiryWheel[K] {
+	return expiryWheel[K]{tick: tick, buckets: make(map[int64]map[K]struct{})}
+}
+
+func (wheel *expiryWheel[K]) tickOf(at time.Time) int64 {
+	return at.UnixNano() / int64(wheel.tick)
+}
+
+// schedule moves key from bucket from to the one deleteAt falls in, and
+// returns the new bucket, zero if the key never expires.
+func (wheel *expiryWheel[K]) schedule(key K, from int64, deleteAt time.Time) int64 {
+	wheel.remove(key, from)
+	if deleteAt.IsZero() {
+		return 0
+	}
+	tick := max(wheel.tickOf(deleteAt), wheel.cursor)
+	bucket, exists := wheel.buckets[tick]
+	if !exists {
+		bucket = make(map[K]struct{})
+		wheel.buckets[tick] = bucket
+	}
+	bucket[key] = struct{}{}
+	return tick
+}
+
+func (wheel *expiryWheel[K]) remove(key K, tick int64) {
+	if bucket, exists := wheel.buckets[tick]; exists {
+		delete(bucket, key)
+		if len(bucket) == 0 {
+			delete(wheel.buckets, tick)
+		}
+	}
+}
+
+// take removes and returns up to n keys from the buckets up to now, leaving
+// the rest for due. As with due, keys from the current tick may not have
+// expired yet.
+func (wheel *expiryWheel[K]) take(now time.Time, n int) []K {
+	end := wheel.tickOf(now)
+	keys := make([]K, 0, n)
+	takeFrom := func(tick int64) {
+		bucket := wheel.buckets[tick]
+		for key := range bucket {
+			if len(keys) == n {
+				return
+			}
+			keys = append(keys, key)
+			delete(bucket, key)
+		}
+		if bucket != nil && len(bucket) == 0 {
+			delete(wheel.buckets, tick)
+		}
+	}
+	if end-wheel.cursor > int64(len(wheel.buckets)) {
+		for tick := range wheel.buckets {
+			if tick <= end && len(keys) < n {
+				takeFrom(tick)
+			}
+		}
+	} else {
+		for tick := wheel.cursor; tick <= end && len(keys) < n; tick++ {
+			takeFrom(tick)
+		}
+	}
+	return keys
+}
+
+// due drops every bucket up to now and returns their keys. The bucket of the
+// current tick is only partly due, so callers must schedule the keys that
+// haven't expired yet again.
+func (wheel *expiryWheel[K]) due(now time.Time) []K {
+	end := wheel.tickOf(now)
+	var keys []K
+	drop := func(tick int64) {
+		for key := range wheel.buckets[tick] {
+			keys = append(keys, key)
+		}
+		delete(wheel.buckets, tick)
+	}
+	if end-wheel.cursor > int64(len(wheel.buckets)) {
+		// after a long pause it's cheaper to look at the buckets we have
+		for tick := range wheel.buckets {
+			if tick <= end {
+				drop(tick)
+			}
+		}
+	} else {
+		for tick := wheel.cursor; tick <= end; tick++ {
+			drop(tick)
+		}
+	}
+	wheel.cursor = end
+	return keys
+}
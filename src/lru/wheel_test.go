@@ -0,0 +1,129 @@
+package lru
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiryWheel(t *testing.T) {
+	start := time.Unix(1000, 0)
+
+	t.Run("drops due buckets only", func(t *testing.T) {
+		wheel := newExpiryWheel[string](10 * time.Millisecond)
+		wheel.schedule("early", 0, start.Add(5*time.Millisecond))
+		wheel.schedule("late", 0, start.Add(25*time.Millisecond))
+
+		assert.Equal(t, []string{"early"}, wheel.due(start.Add(10*time.Millisecond)))
+		assert.Empty(t, wheel.due(start.Add(15*time.Millisecond)))
+		assert.Equal(t, []string{"late"}, wheel.due(start.Add(20*time.Millisecond)), "Current bucket should be returned for checking")
+		assert.Empty(t, wheel.buckets)
+	})
+
+	t.Run("moves rescheduled keys", func(t *testing.T) {
+		wheel := newExpiryWheel[string](10 * time.Millisecond)
+		tick := wheel.schedule("user1", 0, start.Add(5*time.Millisecond))
+		tick = wheel.schedule("user1", tick, start.Add(50*time.Millisecond))
+
+		assert.Empty(t, wheel.due(start.Add(20*time.Millisecond)))
+		wheel.remove("user1", tick)
+		assert.Empty(t, wheel.due(start.Add(time.Second)))
+	})
+
+	t.Run("takes at most n due keys", func(t *testing.T) {
+		wheel := newExpiryWheel[string](10 * time.Millisecond)
+		for i := 0; i < 5; i++ {
+			wheel.schedule(fmt.Sprintf("user%d", i), 0, start)
+		}
+		wheel.schedule("late", 0, start.Add(time.Second))
+
+		assert.Len(t, wheel.take(start.Add(20*time.Millisecond), 3), 3)
+		assert.Len(t, wheel.due(start.Add(20*time.Millisecond)), 2, "Keys that weren't taken should stay due")
+	})
+
+	t.Run("keys that never expire aren't scheduled", func(t *testing.T) {
+		wheel := newExpiryWheel[string](10 * time.Millisecond)
+		assert.Zero(t, wheel.schedule("user1", 0, time.Time{}))
+		assert.Empty(t, wheel.buckets)
+	})
+}
+
+func TestLRUCacheExpiryBurst(t *testing.T) {
+	clock := newFakeClock()
+	lruCache := &InMemoryLRUCache[UserData]{Config: LRUCacheConfig{TTL: 100, ExpiryTick: 20, Logger: slog.New(slog.DiscardHandler)}, clock: clock.Now}
+	// swept by hand below
+	lruCache.Close()
+	for i := 0; i < 10000; i++ {
+		lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+	}
+	lruCache.Set("user0", UserData{ID: 0})
+	clock.Advance(60 * time.Millisecond)
+	lruCache.Set("fresh", UserData{ID: -1})
+	clock.Advance(60 * time.Millisecond)
+
+	lruCache.sweepKeys()
+	assert.Equal(t, uint64(10000), lruCache.Stats().Expirations)
+	assert.Equal(t, []string{"fresh"}, slices.Collect(lruCache.KeysSeq()))
+}
+
+func TestLRUCacheSweep(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+
+	t.Run("removes expired entries in batches", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{TTL: 50, SweepBatch: 10, Logger: slog.New(slog.DiscardHandler)}).(*InMemoryLRUCache[UserData])
+		for i := 0; i < 1000; i++ {
+			lruCache.Set(fmt.Sprintf("user%d", i), UserData{ID: i})
+		}
+		assert.Eventually(t, func() bool {
+			return lruCache.Stats().Expirations == 1000
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("keeps keys re-set after they were found due", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{TTL: 60000}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.expireKeys([]string{"user1"}, time.Now())
+		assert.True(t, lruCache.Has("user1"))
+
+		lruCache.Storage.mu.RLock()
+		defer lruCache.Storage.mu.RUnlock()
+		tick := lruCache.Storage.SafeMap["user1"].expiryTick
+		assert.Contains(t, lruCache.wheel.buckets[tick], "user1", "Key should stay scheduled")
+	})
+}
+
+func TestLRUCacheInlineExpiry(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	// a tick this long keeps the sweeper out of the way
+	config := LRUCacheConfig{ItemLimit: 2, TTL: 50, ExpiryTick: 60000, Logger: slog.New(slog.DiscardHandler)}
+
+	t.Run("expires entries instead of evicting live ones", func(t *testing.T) {
+		config := config
+		config.InlineExpiryBudget = 1
+		lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+		time.Sleep(100 * time.Millisecond)
+
+		lruCache.Set("user3", UserData{ID: 3})
+		stats := lruCache.Stats()
+		assert.Equal(t, uint64(1), stats.Expirations, "Only one entry should be expired inline")
+		assert.Zero(t, stats.Evictions)
+	})
+
+	t.Run("evicts when there is no budget", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(config).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", UserData{ID: 1})
+		lruCache.Set("user2", UserData{ID: 2})
+		time.Sleep(100 * time.Millisecond)
+
+		lruCache.Set("user3", UserData{ID: 3})
+		stats := lruCache.Stats()
+		assert.Zero(t, stats.Expirations)
+		assert.Equal(t, uint64(1), stats.Evictions)
+	})
+}
@@ -0,0 +1,96 @@
+package lru
+
+import (
+	"sync"
+	"time"
+)
+
+// publishedEntry is what lock-free reads of a WriteOnce cache see. Entries
+// never change once written, and their deadline only moves forward, so a
+// published copy is never wrong about a hit.
+type publishedEntry[V any] struct {
+	value    V
+	deleteAt time.Time
+}
+
+// publishedAlias stands in for an alias, so that lookupPublished can follow
+// it to the primary key without the lock.
+type publishedAlias[K comparable] struct {
+	primary K
+}
+
+// newPublished marks the items of safeMap read-only and returns them in a
+// map for lookupPublished, or nil unless Config.WriteOnce is set. With
+// IdleTTL, reads have to record the access under the lock, so nothing is
+// published.
+func (cache *LRUCache[K, V]) newPublished(safeMap map[K]*StorageItem[V]) *sync.Map {
+	if !cache.Config.WriteOnce {
+		return nil
+	}
+	for _, item := range safeMap {
+		item.readOnly = true
+	}
+	if cache.Config.IdleTTL > 0 {
+		return nil
+	}
+	published := new(sync.Map)
+	for key, item := range safeMap {
+		published.Store(key, publishedEntry[V]{value: item.Value, deleteAt: item.DeleteAt})
+	}
+	return published
+}
+
+// publish is called for every item stored. Callers must hold the write
+// lock.
+func (cache *LRUCache[K, V]) publish(key K, item *StorageItem[V]) {
+	if !cache.Config.WriteOnce {
+		return
+	}
+	item.readOnly = true
+	if published := cache.published.Load(); published != nil {
+		published.Store(key, publishedEntry[V]{value: item.Value, deleteAt: item.DeleteAt})
+	}
+}
+
+// publishAliases publishes the aliases of primary. Callers must hold the
+// write lock.
+func (cache *LRUCache[K, V]) publishAliases(primary K) {
+	published := cache.published.Load()
+	if published == nil {
+		return
+	}
+	for _, alias := range cache.aliasesOf[primary] {
+		published.Store(alias, publishedAlias[K]{primary: primary})
+	}
+}
+
+// unpublish drops key, an entry or an alias, from the published map.
+// Callers must hold the write lock.
+func (cache *LRUCache[K, V]) unpublish(key K) {
+	if published := cache.published.Load(); published != nil {
+		published.Delete(key)
+	}
+}
+
+// lookupPublished returns the value of a live entry of a WriteOnce cache
+// without taking the lock. A miss may still be a hit under the lock, for
+// instance if a read extended the TTL since the entry was published.
+func (cache *LRUCache[K, V]) lookupPublished(key K) (V, bool) {
+	var zero V
+	published := cache.published.Load()
+	if published == nil {
+		return zero, false
+	}
+	entry, exists := published.Load(key)
+	if alias, aliased := entry.(publishedAlias[K]); aliased {
+		entry, exists = published.Load(alias.primary)
+	}
+	if !exists {
+		return zero, false
+	}
+	item := entry.(publishedEntry[V])
+	if !item.deleteAt.IsZero() && !cache.expiryPaused.Load() && !cache.now().Before(item.deleteAt) {
+		return zero, false
+	}
+	return item.value, true
+}
@@ -0,0 +1,147 @@
+package lru
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheWriteOnce(t *testing.T) {
+	cacheProvider := InMemoryLRUCacheProvider[UserData]{}
+	alice := UserData{ID: 1, Name: "Alice", Age: 30}
+	bob := UserData{ID: 2, Name: "Bob", Age: 25}
+
+	t.Run("keeps the first value written", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, WriteOnce: true}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		lruCache.Set("user1", bob)
+		assert.ErrorIs(t, lruCache.SetReadOnly("user1", bob), ErrReadOnly)
+		assert.ErrorIs(t, lruCache.Patch("user1", func(user *UserData) { user.Age++ }), ErrReadOnly)
+
+		value, err := lruCache.Get("user1")
+		assert.NoError(t, err)
+		assert.Equal(t, alice, value)
+		assert.True(t, lruCache.Has("user1"))
+		assert.Equal(t, uint64(1), lruCache.Stats().Hits)
+		assert.Equal(t, uint64(3), lruCache.Stats().ReadOnlyRejections)
+	})
+
+	t.Run("returns rejected writes from TrySet", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, WriteOnce: true}).(*InMemoryLRUCache[UserData])
+		assert.NoError(t, lruCache.TrySet("user1", alice))
+		assert.ErrorIs(t, lruCache.TrySet("user1", bob), ErrReadOnly)
+		value, _ := lruCache.Get("user1")
+		assert.Equal(t, alice, value)
+		assert.Equal(t, uint64(1), lruCache.Stats().ReadOnlyRejections)
+
+		lruCache.Freeze()
+		assert.ErrorIs(t, lruCache.TrySet("user2", bob), ErrCacheFrozen)
+	})
+
+	t.Run("serves aliases without the lock", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, WriteOnce: true}).(*InMemoryLRUCache[UserData])
+		lruCache.SetWithAliases("user1", alice, "alice")
+		assert.NoError(t, lruCache.Rename("user1", "user2", false))
+
+		lruCache.Storage.mu.Lock()
+		read := make(chan UserData)
+		go func() {
+			value, _ := lruCache.Get("alice")
+			read <- value
+		}()
+		select {
+		case value := <-read:
+			assert.Equal(t, alice, value)
+		case <-time.After(time.Second):
+			t.Error("Get of an alias waited for the lock")
+		}
+		lruCache.Storage.mu.Unlock()
+
+		assert.True(t, lruCache.Delete("user2"))
+		assert.False(t, lruCache.Has("alice"))
+	})
+
+	t.Run("keeps read entries from idling out", func(t *testing.T) {
+		clock := newFakeClock()
+		lruCache := &InMemoryLRUCache[UserData]{Config: LRUCacheConfig{ItemLimit: 10, IdleTTL: 100, WriteOnce: true}, clock: clock.Now}
+		assert.NoError(t, lruCache.Close())
+		lruCache.Set("user1", alice)
+		for i := 0; i < 5; i++ {
+			clock.Advance(60 * time.Millisecond)
+			assert.True(t, lruCache.Has("user1"))
+		}
+		lruCache.Set("user1", bob)
+		value, _ := lruCache.Get("user1")
+		assert.Equal(t, alice, value)
+
+		clock.Advance(150 * time.Millisecond)
+		assert.False(t, lruCache.Has("user1"))
+	})
+
+	t.Run("panics on rewrites when configured to", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, WriteOnce: true, FrozenWrites: PanicOnFrozenWrites})
+		lruCache.Set("user1", alice)
+		assert.PanicsWithValue(t, ErrReadOnly, func() { lruCache.Set("user1", bob) })
+	})
+
+	t.Run("evicts in write order", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 2, WriteOnce: true})
+		lruCache.Set("user1", alice)
+		lruCache.Set("user2", bob)
+		lruCache.Get("user1")
+		lruCache.Set("user3", bob)
+		assert.False(t, lruCache.Has("user1"))
+		assert.True(t, lruCache.Has("user2"))
+	})
+
+	t.Run("accepts a key again once it left the cache", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 10, TTL: 50, WriteOnce: true}).(*InMemoryLRUCache[UserData])
+		lruCache.Set("user1", alice)
+		assert.True(t, lruCache.Delete("user1"))
+		_, err := lruCache.Get("user1")
+		assert.Error(t, err)
+		lruCache.Set("user1", bob)
+		value, _ := lruCache.Get("user1")
+		assert.Equal(t, bob, value)
+
+		lruCache.Set("user2", alice)
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, lruCache.Has("user2"))
+		lruCache.Set("user2", bob)
+		value, _ = lruCache.Get("user2")
+		assert.Equal(t, bob, value)
+
+		lruCache.Clear()
+		assert.False(t, lruCache.Has("user1"))
+		lruCache.SwapAll(map[string]UserData{"user3": alice})
+		lruCache.Set("user3", bob)
+		value, _ = lruCache.Get("user3")
+		assert.Equal(t, alice, value)
+		assert.NoError(t, lruCache.Rename("user3", "user4", false))
+		assert.False(t, lruCache.Has("user3"))
+		value, _ = lruCache.Get("user4")
+		assert.Equal(t, alice, value)
+	})
+
+	t.Run("serves concurrent reads and writes", func(t *testing.T) {
+		lruCache := cacheProvider.NewLRUCache(LRUCacheConfig{ItemLimit: 100, WriteOnce: true})
+		var wg sync.WaitGroup
+		for worker := 0; worker < 8; worker++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					key := fmt.Sprintf("user%d", i%200)
+					lruCache.Set(key, UserData{ID: i % 200})
+					if value, err := lruCache.Get(key); err == nil {
+						assert.Equal(t, i%200, value.ID)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}